@@ -0,0 +1,43 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// adjacentColor dims the days of the previous and next month shown with
+// --pad-adjacent. Holiday and weekend colors are never applied to them.
+var adjacentColor = Color{110, 110, 110}
+
+// adjacentCells renders count cells of width cw. With opts.PadAdjacent they
+// show the days from, from+1, ... of a neighbouring month; otherwise they are
+// blank.
+func adjacentCells(from, count, cw int, opts monthOptions) string {
+	if !opts.PadAdjacent {
+		return strings.Repeat(" ", cw*count)
+	}
+	var b strings.Builder
+	for d := from; d < from+count; d++ {
+		b.WriteString(rgb(adjacentColor, dayCell(d, cw, false)))
+	}
+	return b.String()
+}
+
+// previousShamsyMonthDays returns the length of the month before jy/jm,
+// which for Farvardin is Esfand of the previous (possibly leap) year.
+func previousShamsyMonthDays(jy, jm int) int {
+	if jm == 1 {
+		return shamsy.MonthDays(jy-1, 12)
+	}
+	return shamsy.MonthDays(jy, jm-1)
+}
+
+// previousGregorianMonthDays returns the length of the month before
+// year/month, which for January is December of the previous year.
+func previousGregorianMonthDays(year, month int) int {
+	if month == 1 {
+		return 31
+	}
+	return gregorianMonthDays(year, month-1)
+}
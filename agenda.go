@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// maxAgendaDays bounds --agenda to about ten years of holiday fetches.
+const maxAgendaDays = 3660
+
+// handleAgenda lists the next n days starting today, one per line, with the
+// Shamsi and Gregorian dates, the weekday and the day's holiday. With
+// --gregorian-events international observances are listed too.
+func handleAgenda(nStr string) error {
+	n, err := strconv.Atoi(nStr)
+	if err != nil || n < 1 || n > maxAgendaDays {
+		return withCode(codeInvalidArgument, fmt.Errorf("invalid --agenda %q: expected a number of days from 1 to %d", nStr, maxAgendaDays))
+	}
+	from := currentDate()
+	to := shamsy.DateFromEpochDays(from.EpochDays() + n - 1)
+	holidays, err := holidaysBetween(from, to)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		d := shamsy.DateFromEpochDays(from.EpochDays() + i)
+		g := gregorianOf(d)
+		weekday := shamsy.GregorianWeekday(g.Year, g.Month, g.Day)
+		var events []string
+		name, holiday := holidays.IsHoliday(d)
+		if holiday {
+			events = append(events, rgb(offday, holidayText(name)))
+		}
+		if gregorianEvents {
+			if o, ok := lookupObservance(g.Month, g.Day); ok {
+				events = append(events, rgb(observanceColor, o))
+			}
+		}
+		dayColor := cyan
+		if holiday || weekday == time.Friday {
+			dayColor = offday
+		}
+		line := fmt.Sprintf("%s  %s  ", rgb(yellow, d.String()),
+			rgb(blue, shamsy.FormatGregorian(g.Year, g.Month, g.Day, "YYYY-MM-DD")))
+		if len(events) == 0 {
+			fmt.Println(line + rgb(dayColor, weekday.String()))
+			continue
+		}
+		fmt.Println(line + rgb(dayColor, fmt.Sprintf("%-9s", weekday)) + "  " + strings.Join(events, "; "))
+	}
+	printFixedOnlyNote()
+	return nil
+}
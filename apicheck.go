@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// reportSchemaCheck is the OnCheck of the holiday API. It prints the check of
+// every response with --verbose and warns whenever an expectation failed, as
+// the API's format may have changed and the calendar may be missing holidays.
+func reportSchemaCheck(check shamsy.SchemaCheck) {
+	if verbose {
+		result := "ok"
+		if !check.OK() {
+			result = "FAILED"
+		}
+		fmt.Fprintf(os.Stderr, "API response check %s: %s\n", check, result)
+	}
+	if !check.OK() {
+		status.Warn(fmt.Sprintf("the holiday API's response for %d looks unusual (%s); its format may have changed and holidays may be missing. Try --fixed-only or --forecast, and please report it",
+			check.Year, strings.Join(check.Problems, "; ")))
+	}
+}
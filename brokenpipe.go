@@ -0,0 +1,29 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// catchBrokenPipe makes a write to stdout fail with EPIPE once the reader of
+// a pipe has gone away, e.g. "scal leaps 1-3000 | head", rather than having
+// the runtime kill scal with SIGPIPE. The failed writes are handled by
+// checkStdout; writes to sockets already fail with EPIPE and are reported as
+// errors by their callers.
+func catchBrokenPipe() {
+	signal.Ignore(syscall.SIGPIPE)
+}
+
+// checkStdout checks the error of a write to stdout. When the reader of a
+// pipe has gone away, scal stops with status 0, as Unix tools do, after
+// reporting what --paranoid found; output written with -o goes to a file, so
+// it never fails this way. Other errors are returned.
+func checkStdout(err error) error {
+	if errors.Is(err, syscall.EPIPE) {
+		reportMismatches()
+		os.Exit(0)
+	}
+	return err
+}
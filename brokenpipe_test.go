@@ -0,0 +1,46 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestCheckStdout(t *testing.T) {
+	if err := checkStdout(nil); err != nil {
+		t.Errorf("checkStdout(nil) = %v", err)
+	}
+	other := errors.New("disk full")
+	if err := checkStdout(other); err != other {
+		t.Errorf("checkStdout(%v) = %v, want the error back", other, err)
+	}
+}
+
+func TestBrokenPipeExitsZero(t *testing.T) {
+	if os.Getenv("SCAL_TEST_BROKEN_PIPE") == "1" {
+		catchBrokenPipe()
+		for {
+			if _, err := fmt.Println(strings.Repeat("1404/07/10 ", 100)); err != nil {
+				checkStdout(err)
+				os.Exit(3)
+			}
+		}
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^TestBrokenPipeExitsZero$")
+	cmd.Env = append(os.Environ(), "SCAL_TEST_BROKEN_PIPE=1")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	// The reader goes away, as head does once it has its lines.
+	stdout.Close()
+	if err := cmd.Wait(); err != nil {
+		t.Errorf("writing to a closed pipe: %v, want exit status 0", err)
+	}
+}
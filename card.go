@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+	"github.com/rivo/uniseg"
+	"strings"
+	"unicode/utf8"
+)
+
+// cardWidth is the total width of the --card box, borders included.
+const cardWidth = 40
+
+// visibleWidth returns the number of terminal columns s occupies, ignoring
+// color codes. Wide characters such as emoji count as two columns.
+func visibleWidth(s string) int {
+	return uniseg.StringWidth(stripAnsiCodes(s))
+}
+
+// handleCard converts a date and prints the result as a small box with the
+// mini month of the target calendar, the converted day highlighted.
+func handleCard(dateStr string, isGregorian bool) error {
+	gy, gm, gd, sh, err := parseCalendarDate(dateStr, isGregorian)
+	if err != nil {
+		return err
+	}
+	holidays, _ := fetchHolidays(sh.Year)
+
+	inner := cardWidth - 4
+	border := func(left, right string) {
+		fmt.Println(rgb(cyan, left+strings.Repeat("─", cardWidth-2)+right))
+	}
+	line := func(s string) {
+		if w := visibleWidth(s); w < inner {
+			s += strings.Repeat(" ", inner-w)
+		}
+		fmt.Println(rgb(cyan, "│") + " " + s + " " + rgb(cyan, "│"))
+	}
+	field := func(label, value string) {
+		line(fmt.Sprintf("%s %s", rgb(green, fmt.Sprintf("%-10s", label)), value))
+	}
+
+	border("┌", "┐")
+	field("Shamsi", rgb(yellow, fmt.Sprintf("%s  %d %s", sh, sh.Day, shamsyMonths[sh.Month-1])))
+	field("Gregorian", rgb(blue, shamsy.FormatGregorian(gy, gm, gd, "YYYY/MM/DD  D MonthName")))
+	field("Weekday", rgb(cyan, shamsy.WeekdayName(gy, gm, gd)))
+	if name, ok := holidays.IsHoliday(sh); ok {
+		name = holidayText(name)
+		if max := inner - 11; utf8.RuneCountInString(name) > max {
+			name = string([]rune(name)[:max-1]) + "…"
+		}
+		field("Holiday", rgb(offday, name))
+	}
+	border("├", "┤")
+	opts := monthOptions{Mini: true, NoTrailingNewline: true}
+	// The card shows the month of the target calendar.
+	target := renderOptions{Gregorian: true, Year: gy, Month: gm, Highlight: gd, Holidays: holidays, monthOptions: opts}
+	if isGregorian {
+		target = renderOptions{Year: sh.Year, Month: sh.Month, Highlight: sh.Day, Holidays: holidays, monthOptions: opts}
+	}
+	month := captureStdout(func() { renderMonth(target) })
+	indent := strings.Repeat(" ", (inner-opts.monthWidth())/2)
+	for _, l := range strings.Split(strings.TrimSuffix(month, "\n"), "\n") {
+		line(indent + l)
+	}
+	border("└", "┘")
+	return nil
+}
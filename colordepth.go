@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Color depths rgb can emit. depthNone is the same as --no-color.
+const (
+	depthTrueColor = "truecolor"
+	depth256       = "256"
+	depth16        = "16"
+	depthNone      = "none"
+)
+
+// colorDepth selects the escape codes rgb emits: 24-bit colors, or the
+// nearest color of the 256- or 16-color palette for terminals without
+// truecolor support.
+var colorDepth = depthTrueColor
+
+// parseColorDepth validates --color-depth; an empty value detects the depth
+// of the terminal.
+func parseColorDepth(s string) (string, error) {
+	switch strings.ToLower(s) {
+	case "":
+		return detectColorDepth(), nil
+	case depthTrueColor, "24bit":
+		return depthTrueColor, nil
+	case depth256:
+		return depth256, nil
+	case depth16:
+		return depth16, nil
+	case depthNone:
+		return depthNone, nil
+	}
+	return "", withCode(codeInvalidArgument, fmt.Errorf("invalid --color-depth %q (want truecolor, 256, 16 or none)", s))
+}
+
+// detectColorDepth guesses the color depth of the terminal from $COLORTERM
+// and $TERM. Terminals that set neither are assumed to support truecolor, as
+// scal always did, so that only terminals known to be limited change.
+func detectColorDepth() string {
+	colorTerm := strings.ToLower(os.Getenv("COLORTERM"))
+	if colorTerm == "truecolor" || colorTerm == "24bit" {
+		return depthTrueColor
+	}
+	term := strings.ToLower(os.Getenv("TERM"))
+	switch {
+	case term == "":
+		return depthTrueColor
+	case term == "dumb":
+		return depthNone
+	case strings.Contains(term, "truecolor") || strings.Contains(term, "direct"):
+		return depthTrueColor
+	case strings.Contains(term, "256color"):
+		return depth256
+	}
+	return depth16
+}
+
+// colorCode returns the SGR parameters selecting c at the current depth.
+func colorCode(c Color) string {
+	switch colorDepth {
+	case depth256:
+		return fmt.Sprintf("38;5;%d", nearest256(c))
+	case depth16:
+		if code, ok := dayRole16(c); ok {
+			return fmt.Sprintf("%d", code)
+		}
+		return fmt.Sprintf("%d", nearest16(c))
+	}
+	return fmt.Sprintf("38;2;%d;%d;%d", c.r, c.g, c.b)
+}
+
+// dayRole16 returns the 16-color code of the colors that tell the days of
+// a month apart. The nearest palette color would merge some of them, e.g.
+// the half-day tint and today's highlight both become yellow, so each role
+// gets its own code. The terminal's theme decides what the 16 colors look
+// like, so the codes are the same for the light palette.
+func dayRole16(c Color) (int, bool) {
+	switch c {
+	case yellow: // today
+		return 93, true
+	case offday:
+		return 91, true
+	case weekendColor:
+		return 31, true
+	case halfDayColor:
+		return 35, true
+	case markColor:
+		return 95, true
+	}
+	return 0, false
+}
+
+// cubeLevels are the channel values of the 6×6×6 color cube of the
+// 256-color palette.
+var cubeLevels = []int{0, 95, 135, 175, 215, 255}
+
+// nearest256 returns the index of the color of the xterm 256-color palette
+// closest to c: a color of the cube (16–231) or a gray (232–255).
+func nearest256(c Color) int {
+	level := func(v int) int {
+		best := 0
+		for i, l := range cubeLevels {
+			if abs(v-l) < abs(v-cubeLevels[best]) {
+				best = i
+			}
+		}
+		return best
+	}
+	r, g, b := level(c.r), level(c.g), level(c.b)
+	cube := Color{cubeLevels[r], cubeLevels[g], cubeLevels[b]}
+	index := 16 + 36*r + 6*g + b
+	gray := min(max(((c.r+c.g+c.b)/3-8+5)/10, 0), 23)
+	grayLevel := 8 + 10*gray
+	if colorDistance(c, Color{grayLevel, grayLevel, grayLevel}) < colorDistance(c, cube) {
+		return 232 + gray
+	}
+	return index
+}
+
+// ansi16 are the colors of the 16-color palette as xterm draws them, in
+// the order of their SGR codes 30–37 and 90–97.
+var ansi16 = []Color{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// nearest16 returns the SGR foreground code of the color of the 16-color
+// palette closest to c.
+func nearest16(c Color) int {
+	best := 0
+	for i, p := range ansi16 {
+		if colorDistance(c, p) < colorDistance(c, ansi16[best]) {
+			best = i
+		}
+	}
+	if best < 8 {
+		return 30 + best
+	}
+	return 90 + best - 8
+}
+
+// colorDistance is the squared distance between two colors, weighted for
+// how the eye perceives each channel.
+func colorDistance(a, b Color) int {
+	dr, dg, db := a.r-b.r, a.g-b.g, a.b-b.b
+	return 2*dr*dr + 4*dg*dg + 3*db*db
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
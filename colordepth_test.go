@@ -0,0 +1,112 @@
+package main
+
+import "testing"
+
+func TestParseColorDepth(t *testing.T) {
+	tests := []struct {
+		in, want string
+		wantErr  bool
+	}{
+		{"truecolor", depthTrueColor, false},
+		{"24bit", depthTrueColor, false},
+		{"TrueColor", depthTrueColor, false},
+		{"256", depth256, false},
+		{"16", depth16, false},
+		{"none", depthNone, false},
+		{"8", "", true},
+		{"full", "", true},
+	}
+	for _, tt := range tests {
+		got, err := parseColorDepth(tt.in)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("parseColorDepth(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+		if err != nil && errorCode(err) != codeInvalidArgument {
+			t.Errorf("parseColorDepth(%q): error code %s, want %s", tt.in, errorCode(err), codeInvalidArgument)
+		}
+	}
+}
+
+func TestDetectColorDepth(t *testing.T) {
+	tests := []struct {
+		colorTerm, term, want string
+	}{
+		{"", "", depthTrueColor},
+		{"truecolor", "xterm", depthTrueColor},
+		{"24bit", "xterm-256color", depthTrueColor},
+		{"", "xterm-direct", depthTrueColor},
+		{"", "xterm-256color", depth256},
+		{"", "screen-256color", depth256},
+		{"", "xterm", depth16},
+		{"", "linux", depth16},
+		{"", "dumb", depthNone},
+	}
+	for _, tt := range tests {
+		t.Setenv("COLORTERM", tt.colorTerm)
+		t.Setenv("TERM", tt.term)
+		if got := detectColorDepth(); got != tt.want {
+			t.Errorf("COLORTERM=%q TERM=%q: detectColorDepth() = %q, want %q", tt.colorTerm, tt.term, got, tt.want)
+		}
+		if got, err := parseColorDepth(""); err != nil || got != tt.want {
+			t.Errorf("COLORTERM=%q TERM=%q: parseColorDepth(\"\") = %q, %v, want %q", tt.colorTerm, tt.term, got, err, tt.want)
+		}
+	}
+}
+
+func TestNearestColors(t *testing.T) {
+	tests := []struct {
+		c           Color
+		want256     int
+		want16      int
+		description string
+	}{
+		{Color{0, 0, 0}, 16, 30, "black"},
+		{Color{255, 255, 255}, 231, 97, "white"},
+		{Color{255, 0, 0}, 196, 91, "bright red"},
+		{Color{205, 0, 0}, 160, 31, "red"},
+		{Color{128, 128, 128}, 244, 90, "gray"},
+		{Color{0, 255, 255}, 51, 96, "cyan"},
+	}
+	for _, tt := range tests {
+		if got := nearest256(tt.c); got != tt.want256 {
+			t.Errorf("nearest256(%s %v) = %d, want %d", tt.description, tt.c, got, tt.want256)
+		}
+		if got := nearest16(tt.c); got != tt.want16 {
+			t.Errorf("nearest16(%s %v) = %d, want %d", tt.description, tt.c, got, tt.want16)
+		}
+	}
+}
+
+func TestDayRoles16Distinct(t *testing.T) {
+	savedDepth := colorDepth
+	colorDepth = depth16
+	saved := []Color{offday, weekendColor, red, green, blue, yellow, cyan, purple, observanceColor, halfDayColor, adjacentColor, markColor, estimatedColor}
+	savedTints := weekdayTints
+	t.Cleanup(func() {
+		colorDepth = savedDepth
+		offday, weekendColor, red, green, blue, yellow, cyan, purple, observanceColor, halfDayColor, adjacentColor, markColor, estimatedColor =
+			saved[0], saved[1], saved[2], saved[3], saved[4], saved[5], saved[6], saved[7], saved[8], saved[9], saved[10], saved[11], saved[12]
+		weekdayTints = savedTints
+	})
+
+	for _, palette := range []string{"dark", "light"} {
+		if palette == "light" {
+			useLightPalette()
+		}
+		roles := map[string]Color{
+			"today":   shamsyDayColor(1404, 7, 10, 10, nil),
+			"holiday": offday,
+			"weekend": weekendColor,
+			"halfday": halfDayColor,
+			"mark":    markColor,
+		}
+		seen := map[string]string{}
+		for role, c := range roles {
+			code := colorCode(c)
+			if other, ok := seen[code]; ok {
+				t.Errorf("%s palette: %s and %s both use SGR %s", palette, role, other, code)
+			}
+			seen[code] = role
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// comparedMonth is one side of compare-month: a month and its holidays.
+type comparedMonth struct {
+	year, month int
+	holidays    *shamsy.HolidayCalendar
+}
+
+// parseComparedMonth parses a YEAR/MONTH (or YEAR-MONTH) argument of
+// compare-month, a Gregorian month with isGregorian.
+func parseComparedMonth(s string, isGregorian bool) (int, int, error) {
+	parts := strings.Split(strings.ReplaceAll(s, "-", "/"), "/")
+	if len(parts) != 2 {
+		return 0, 0, withCode(codeInvalidArgument, fmt.Errorf("invalid month %q, expected YEAR/MONTH", s))
+	}
+	if !isGregorian {
+		return parseYearMonth(parts[0], parts[1])
+	}
+	y, err := strconv.Atoi(parts[0])
+	if err != nil || !yearInRange(y, true) {
+		return 0, 0, withCode(codeInvalidArgument, fmt.Errorf("invalid year argument %q (supported: %s)", parts[0], supportedRange(true)))
+	}
+	m, err := strconv.Atoi(parts[1])
+	if err != nil || m < 1 || m > 12 {
+		return 0, 0, withCode(codeInvalidArgument, fmt.Errorf("invalid month argument %q: month must be between 1 and 12", parts[1]))
+	}
+	return y, m, nil
+}
+
+// loadComparedMonth fetches the holidays of a month. A Gregorian month spans
+// two Shamsi years, whose holidays are merged.
+func loadComparedMonth(y, m int, isGregorian bool) (comparedMonth, error) {
+	if !isGregorian {
+		cal, err := fetchMonthHolidays(y, m)
+		return comparedMonth{y, m, cal}, err
+	}
+	jy, _, _ := fromGregorian(y, 1, 1)
+	cal, err := fetchHolidays(jy)
+	if err != nil {
+		return comparedMonth{}, err
+	}
+	next, _ := fetchHolidays(jy + 1)
+	return comparedMonth{y, m, shamsy.Merge(cal, next)}, nil
+}
+
+// days returns the length of the month.
+func (c comparedMonth) days(isGregorian bool) int {
+	if isGregorian {
+		return gregorianMonthDays(c.year, c.month)
+	}
+	return shamsy.MonthDays(c.year, c.month)
+}
+
+// holidayDays returns which days of the month are holidays.
+func (c comparedMonth) holidayDays(isGregorian bool) map[int]bool {
+	days := map[int]bool{}
+	for d := 1; d <= c.days(isGregorian); d++ {
+		var ok bool
+		if isGregorian {
+			_, ok = c.holidays.IsGregorianHoliday(c.year, c.month, d)
+		} else {
+			_, ok = c.holidays.IsHoliday(shamsy.Date{Year: c.year, Month: c.month, Day: d})
+		}
+		days[d] = ok
+	}
+	return days
+}
+
+// firstWeekday returns the weekday name of the month's 1st.
+func (c comparedMonth) firstWeekday(isGregorian bool) string {
+	if isGregorian {
+		return shamsy.WeekdayName(c.year, c.month, 1)
+	}
+	gy, gm, gd := toGregorian(c.year, c.month, 1)
+	return shamsy.WeekdayName(gy, gm, gd)
+}
+
+// handleCompareMonth implements "compare-month YEAR/MONTH YEAR/MONTH": the two
+// months side by side, each with its own holidays, and a summary of what
+// differs between them.
+func handleCompareMonth(args []string, isGregorian bool) error {
+	if len(args) != 2 {
+		return withCode(codeUsage, fmt.Errorf("usage: shamsy-calendar compare-month YEAR/MONTH YEAR/MONTH"))
+	}
+	var months [2]comparedMonth
+	for i, arg := range args {
+		y, m, err := parseComparedMonth(arg, isGregorian)
+		if err != nil {
+			return err
+		}
+		if months[i], err = loadComparedMonth(y, m, isGregorian); err != nil {
+			return err
+		}
+	}
+
+	opts := monthOptions{}
+	if available := terminalWidth(); available > 0 && yearWidth(2, opts) > available {
+		opts.Mini = true
+	}
+	renders := make([]func(opts monthOptions), len(months))
+	for i, c := range months {
+		renders[i] = func(opts monthOptions) {
+			renderMonth(renderOptions{Gregorian: isGregorian, Year: c.year, Month: c.month, Highlight: noHighlight, Holidays: c.holidays, monthOptions: opts})
+		}
+	}
+	printMonthColumns(renders, opts)
+
+	label := func(c comparedMonth) string { return fmt.Sprintf("%04d/%02d", c.year, c.month) }
+	a, b := months[0], months[1]
+	aHolidays, bHolidays := a.holidayDays(isGregorian), b.holidayDays(isGregorian)
+	count := func(days map[int]bool) int {
+		n := 0
+		for _, ok := range days {
+			if ok {
+				n++
+			}
+		}
+		return n
+	}
+	// Days are compared by number; a day missing from the shorter month
+	// counts as not a holiday.
+	var onlyA, onlyB []string
+	for d := 1; d <= 31; d++ {
+		switch {
+		case aHolidays[d] && !bHolidays[d]:
+			onlyA = append(onlyA, strconv.Itoa(d))
+		case bHolidays[d] && !aHolidays[d]:
+			onlyB = append(onlyB, strconv.Itoa(d))
+		}
+	}
+	list := func(days []string) string {
+		if len(days) == 0 {
+			return "none"
+		}
+		return strings.Join(days, ", ")
+	}
+	fmt.Println(rgb(cyan, strings.Repeat("-", 60)))
+	fmt.Printf("%s: %s\n", rgb(green, "First day"), rgb(cyan, fmt.Sprintf("%s %s / %s %s", label(a), a.firstWeekday(isGregorian), label(b), b.firstWeekday(isGregorian))))
+	fmt.Printf("%s: %s\n", rgb(green, "Holidays"), rgb(offday, fmt.Sprintf("%d / %d", count(aHolidays), count(bHolidays))))
+	fmt.Printf("%s: %s\n", rgb(green, "Only in "+label(a)), rgb(offday, list(onlyA)))
+	fmt.Printf("%s: %s\n", rgb(green, "Only in "+label(b)), rgb(offday, list(onlyB)))
+	printFixedOnlyNote()
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// handleCompareYears implements "--compare YEAR1 YEAR2": a table of how two
+// Shamsi years differ in length, leap status, Nowruz and holiday count,
+// followed by the holidays whose date moved between them. Fixed holidays
+// keep their date; the Hijri-based ones move about 11 days a year.
+func handleCompareYears(y1Str, y2Str string) error {
+	var years [2]int
+	var holidays [2]*shamsy.HolidayCalendar
+	for i, s := range []string{y1Str, y2Str} {
+		y, err := strconv.Atoi(s)
+		if err != nil || !shamsy.InRange(y) {
+			return withCode(codeInvalidArgument, fmt.Errorf("invalid year argument %q (supported: %s)", s, supportedRange(false)))
+		}
+		years[i] = y
+		if holidays[i], err = fetchHolidays(y); err != nil {
+			return err
+		}
+	}
+
+	row := func(label string, color Color, values [2]string) {
+		fmt.Printf("%s%s%s\n", rgb(green, fmt.Sprintf("%-16s", label)),
+			rgb(color, fmt.Sprintf("%-24s", values[0])), rgb(color, values[1]))
+	}
+	var leap, length, nowruz, nowruzG, count [2]string
+	for i, y := range years {
+		leap[i] = "no"
+		if shamsy.IsLeapYear(y) {
+			leap[i] = "yes"
+		}
+		days := 365
+		if shamsy.IsLeapYear(y) {
+			days = 366
+		}
+		length[i] = fmt.Sprintf("%d days", days)
+		gy, gm, gd := toGregorian(y, 1, 1)
+		nowruz[i] = shamsy.WeekdayName(gy, gm, gd)
+		nowruzG[i] = shamsy.FormatGregorian(gy, gm, gd, "YYYY/MM/DD")
+		count[i] = strconv.Itoa(len(holidays[i].Holidays()))
+	}
+	fmt.Println(rgb(cyan, strings.Repeat("=", 60)))
+	fmt.Println(rgb(purple, withIcon("📊", fmt.Sprintf("Comparing %d and %d", years[0], years[1]))))
+	fmt.Println(rgb(cyan, strings.Repeat("-", 60)))
+	row("", purple, [2]string{strconv.Itoa(years[0]), strconv.Itoa(years[1])})
+	row("Leap year", cyan, leap)
+	row("Length", cyan, length)
+	row("Nowruz", yellow, nowruz)
+	row("", blue, nowruzG)
+	row("Holidays", offday, count)
+
+	// Holidays are matched by event name; a lunar one can fall twice in a
+	// Shamsi year, so each name maps to all of its dates.
+	dates := [2]map[string][]string{{}, {}}
+	var names []string
+	for i := range years {
+		for _, h := range holidays[i].Holidays() {
+			for _, event := range strings.Split(h.Name, "; ") {
+				key := normalizeHolidayName(event)
+				if dates[0][key] == nil && dates[1][key] == nil {
+					names = append(names, event)
+				}
+				dates[i][key] = append(dates[i][key], fmt.Sprintf("%02d/%02d", h.Date.Month, h.Date.Day))
+			}
+		}
+	}
+	list := func(d []string) string {
+		if len(d) == 0 {
+			return "—"
+		}
+		return strings.Join(d, ", ")
+	}
+	fmt.Println(rgb(cyan, strings.Repeat("-", 60)))
+	moved := 0
+	for _, name := range names {
+		key := normalizeHolidayName(name)
+		a, b := list(dates[0][key]), list(dates[1][key])
+		if a == b {
+			continue
+		}
+		if moved == 0 {
+			fmt.Println(rgb(green, "Moved holidays (month/day):"))
+		}
+		moved++
+		fmt.Printf("- %s  %s\n", rgb(yellow, fmt.Sprintf("%-14s → %-14s", a, b)), rgb(offday, holidayText(name)))
+	}
+	if moved == 0 {
+		fmt.Printf("%s: %s\n", rgb(green, "Moved holidays"), rgb(cyan, "none"))
+	}
+	printFixedOnlyNote()
+	fmt.Println(rgb(cyan, strings.Repeat("=", 60)))
+	return nil
+}
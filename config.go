@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// config holds the optional user settings read from config.json.
+type config struct {
+	// Rules add recurring off days, e.g. a company's every-other Thursday.
+	Rules []ruleConfig `json:"rules"`
+	// History records -c conversions for the history command.
+	History bool `json:"history"`
+	// DataURL is where update-data downloads the data bundle from.
+	DataURL string `json:"data_url"`
+	// MinShamsiYear and MinGregorianYear are the smallest year arguments
+	// accepted without --force-year; 0 means the defaults, 1000 and 1500.
+	MinShamsiYear    int `json:"min_shamsi_year"`
+	MinGregorianYear int `json:"min_gregorian_year"`
+	// Profiles are named sets of settings selected with --profile.
+	Profiles map[string]profileConfig `json:"profiles"`
+	// Defaults are flag values used when the flag is not given, e.g.
+	// {"week-start": "monday"}; --save-config writes them.
+	Defaults map[string]string `json:"defaults"`
+}
+
+// ruleConfig is one entry of the "rules" section.
+type ruleConfig struct {
+	Match string `json:"match"` // e.g. "last wednesday of month"
+	Name  string `json:"name"`
+}
+
+// userConfig is the loaded configuration; empty when there is no file.
+var userConfig config
+
+// configFile returns where the configuration is read from: --config when
+// given, otherwise config.json in the user config directory.
+func configFile(override string) (string, error) {
+	if override != "" {
+		return override, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", withCode(codeConfig, fmt.Errorf("failed to get config directory: %v", err))
+	}
+	return filepath.Join(dir, "shamsy_calendar", "config.json"), nil
+}
+
+// loadConfig reads the configuration file. A missing default file is not an
+// error, but a missing file named with --config is.
+func loadConfig(override string) (config, error) {
+	var cfg config
+	path, err := configFile(override)
+	if err != nil {
+		return cfg, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && override == "" {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, withCode(codeConfig, fmt.Errorf("failed to read config: %v", err))
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, withCode(codeConfig, fmt.Errorf("invalid config %s: %v", path, err))
+	}
+	return cfg, nil
+}
+
+// applyConfig loads the configuration and the rules it defines.
+func applyConfig(override string) error {
+	cfg, err := loadConfig(override)
+	if err != nil {
+		return err
+	}
+	rules, err := loadRules(cfg)
+	if err != nil {
+		return err
+	}
+	userConfig, holidayRules = cfg, rules
+	return nil
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// dataBundle replaces the embedded translation and icon tables and the
+// built-in fixed holidays, so that old binaries can pick up new holiday names
+// and dates without a release.
+type dataBundle struct {
+	Version       string                `json:"version"`
+	HolidayNames  map[string]string     `json:"holiday_names"`  // as data/holiday_names.json
+	HolidayEmoji  map[string]string     `json:"holiday_emoji"`  // as data/holiday_emoji.json
+	FixedHolidays []shamsy.FixedHoliday `json:"fixed_holidays"` // as shamsy/fixed.go
+}
+
+// dataVersion is the version of the data bundle in use; empty while the
+// embedded tables are used.
+var dataVersion string
+
+// dataBundleFile returns where update-data installs the bundle. Its SHA-256
+// checksum is kept next to it with a ".sha256" suffix.
+func dataBundleFile() (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "data_bundle.json"), nil
+}
+
+// parseDataBundle checks data against the hex SHA-256 checksum sum and
+// decodes it.
+func parseDataBundle(data []byte, sum string) (dataBundle, error) {
+	var bundle dataBundle
+	actual := sha256.Sum256(data)
+	if hex.EncodeToString(actual[:]) != strings.ToLower(sum) {
+		return bundle, fmt.Errorf("checksum mismatch: expected %s, got %x", sum, actual)
+	}
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return bundle, fmt.Errorf("invalid data bundle: %v", err)
+	}
+	if bundle.Version == "" || len(bundle.HolidayNames) == 0 {
+		return bundle, fmt.Errorf("invalid data bundle: missing version or holiday names")
+	}
+	for _, f := range bundle.FixedHolidays {
+		// Esfand 30 is checked against a leap year; it is skipped in the
+		// common ones.
+		if f.Month < 1 || f.Month > 12 || f.Day < 1 || f.Day > shamsy.MonthDays(1403, f.Month) || f.Name == "" {
+			return bundle, fmt.Errorf("invalid data bundle: bad fixed holiday %d/%d %q", f.Month, f.Day, f.Name)
+		}
+	}
+	return bundle, nil
+}
+
+// readChecksum returns the first field of a checksum file, which may be in
+// sha256sum format ("HEX  FILE").
+func readChecksum(data []byte) string {
+	if fields := strings.Fields(string(data)); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
+
+// readInstalledBundle reads and verifies the bundle installed in path.
+func readInstalledBundle(path string) (dataBundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return dataBundle{}, err
+	}
+	sum, err := os.ReadFile(path + ".sha256")
+	if err != nil {
+		return dataBundle{}, err
+	}
+	return parseDataBundle(data, readChecksum(sum))
+}
+
+// loadDataBundle switches to the installed data bundle when there is one. A
+// bundle that fails verification is ignored with a warning and the embedded
+// tables stay in use.
+func loadDataBundle() {
+	path, err := dataBundleFile()
+	if err != nil {
+		return
+	}
+	bundle, err := readInstalledBundle(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		status.Warn(fmt.Sprintf("ignoring data bundle %s: %v", path, err))
+		return
+	}
+	setHolidayNames(bundle.HolidayNames)
+	if len(bundle.HolidayEmoji) > 0 {
+		holidayEmoji = bundle.HolidayEmoji
+	}
+	if len(bundle.FixedHolidays) > 0 {
+		fixedHolidayTable = bundle.FixedHolidays
+	}
+	dataVersion = bundle.Version
+}
+
+// download fetches url under fetchContext.
+func download(url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(fetchContext, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: unexpected status code: %d", url, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// installDataBundle writes data and its checksum to path. The previous bundle
+// is kept until the new one has been read back and verified, and restored if
+// that fails.
+func installDataBundle(path string, data []byte, sum string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	// The checksum may be missing, e.g. removed by hand; only what exists is
+	// moved aside, and put back if anything later fails.
+	var backedUp []string
+	putBack := func() {
+		for _, suffix := range backedUp {
+			os.Rename(path+suffix+".prev", path+suffix)
+		}
+	}
+	for _, suffix := range []string{"", ".sha256"} {
+		err := os.Rename(path+suffix, path+suffix+".prev")
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			putBack()
+			return err
+		}
+		backedUp = append(backedUp, suffix)
+	}
+	restore := func(cause error) error {
+		os.Remove(path)
+		os.Remove(path + ".sha256")
+		if len(backedUp) > 0 {
+			putBack()
+			return fmt.Errorf("%v; the previous bundle was restored", cause)
+		}
+		return cause
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return restore(err)
+	}
+	if err := os.WriteFile(path+".sha256", []byte(sum+"\n"), 0644); err != nil {
+		return restore(err)
+	}
+	if _, err := readInstalledBundle(path); err != nil {
+		return restore(err)
+	}
+	for _, suffix := range backedUp {
+		os.Remove(path + suffix + ".prev")
+	}
+	return nil
+}
+
+// handleUpdateData implements "update-data [--url URL] [--verify-only]": it
+// downloads the data bundle from URL and its checksum from URL.sha256, and
+// installs the bundle into the cache directory once the checksum matches.
+func handleUpdateData(args []string) error {
+	fs := flag.NewFlagSet("update-data", flag.ContinueOnError)
+	url := fs.String("url", userConfig.DataURL, "URL of the data bundle (default: data_url in the config)")
+	verifyOnly := fs.Bool("verify-only", false, "Download and verify the bundle without installing it")
+	if err := fs.Parse(args); err != nil {
+		return withCode(codeUsage, err)
+	}
+	if fs.NArg() != 0 {
+		return withCode(codeUsage, fmt.Errorf("usage: update-data [--url URL] [--verify-only]"))
+	}
+	if *url == "" {
+		return withCode(codeUsage, fmt.Errorf("no data bundle URL: pass --url or set \"data_url\" in the config"))
+	}
+	status.Start("Downloading data bundle...")
+	data, err := download(*url)
+	var sum []byte
+	if err == nil {
+		sum, err = download(*url + ".sha256")
+	}
+	status.Done()
+	if err != nil {
+		return fmt.Errorf("failed to download data bundle: %v", err)
+	}
+	bundle, err := parseDataBundle(data, readChecksum(sum))
+	if err != nil {
+		return fmt.Errorf("rejected data bundle from %s: %v", *url, err)
+	}
+	if *verifyOnly {
+		fmt.Printf("Data bundle %s verified (%d holiday names, %d fixed holidays)\n", bundle.Version, len(bundle.HolidayNames), len(bundle.FixedHolidays))
+		return nil
+	}
+	path, err := dataBundleFile()
+	if err != nil {
+		return err
+	}
+	if err := installDataBundle(path, data, readChecksum(sum)); err != nil {
+		return fmt.Errorf("failed to install data bundle: %v", err)
+	}
+	fmt.Printf("Installed data bundle %s (%d holiday names, %d fixed holidays) in %s\n", bundle.Version, len(bundle.HolidayNames), len(bundle.FixedHolidays), path)
+	return nil
+}
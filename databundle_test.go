@@ -0,0 +1,148 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// bundleData returns a data bundle of the given version and its checksum.
+func bundleData(version string) ([]byte, string) {
+	data := []byte(`{"version": "` + version + `", "holiday_names": {"جشن نوروز": "Nowruz"},
+	"fixed_holidays": [{"month": 1, "day": 1, "name": "جشن نوروز"}, {"month": 12, "day": 30, "name": "روز آزمایشی"}]}`)
+	sum := sha256.Sum256(data)
+	return data, hex.EncodeToString(sum[:])
+}
+
+// installedVersion returns the version of the bundle installed in path.
+func installedVersion(t *testing.T, path string) string {
+	t.Helper()
+	bundle, err := readInstalledBundle(path)
+	if err != nil {
+		t.Fatalf("installed bundle: %v", err)
+	}
+	return bundle.Version
+}
+
+func TestInstallDataBundleRollback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data_bundle.json")
+	v1, sum1 := bundleData("v1")
+	if err := installDataBundle(path, v1, sum1); err != nil {
+		t.Fatal(err)
+	}
+	v2, _ := bundleData("v2")
+	err := installDataBundle(path, v2, sum1)
+	if err == nil || !strings.Contains(err.Error(), "previous bundle was restored") {
+		t.Fatalf("installing a bundle with the wrong checksum: %v, want a restore", err)
+	}
+	if got := installedVersion(t, path); got != "v1" {
+		t.Errorf("after the failed install: version %s, want v1", got)
+	}
+	for _, suffix := range []string{".prev", ".sha256.prev"} {
+		if _, err := os.Stat(path + suffix); !os.IsNotExist(err) {
+			t.Errorf("%s left behind: %v", suffix, err)
+		}
+	}
+}
+
+func TestInstallDataBundleMissingChecksum(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data_bundle.json")
+	v1, _ := bundleData("v1")
+	if err := os.WriteFile(path, v1, 0644); err != nil {
+		t.Fatal(err)
+	}
+	v2, sum2 := bundleData("v2")
+	if err := installDataBundle(path, v2, "0000"); err == nil {
+		t.Fatal("installing a bundle with the wrong checksum succeeded")
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != string(v1) {
+		t.Errorf("the bundle without a checksum was not restored: %q, %v", data, err)
+	}
+	if _, err := os.Stat(path + ".sha256"); !os.IsNotExist(err) {
+		t.Errorf("a checksum was left for the restored bundle: %v", err)
+	}
+	if err := installDataBundle(path, v2, sum2); err != nil {
+		t.Fatalf("replacing a bundle without a checksum: %v", err)
+	}
+	if got := installedVersion(t, path); got != "v2" {
+		t.Errorf("version %s, want v2", got)
+	}
+}
+
+func TestInstallDataBundleBackupFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data_bundle.json")
+	v1, sum1 := bundleData("v1")
+	if err := installDataBundle(path, v1, sum1); err != nil {
+		t.Fatal(err)
+	}
+	// A non-empty directory in the way makes the second rename fail after
+	// the bundle itself has been moved aside.
+	if err := os.MkdirAll(filepath.Join(path+".sha256.prev", "x"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	v2, sum2 := bundleData("v2")
+	if err := installDataBundle(path, v2, sum2); err == nil {
+		t.Fatal("install succeeded with the backup blocked")
+	}
+	if got := installedVersion(t, path); got != "v1" {
+		t.Errorf("after the failed backup: version %s, want v1", got)
+	}
+}
+
+func TestParseDataBundleFixedHolidays(t *testing.T) {
+	data, sum := bundleData("v1")
+	bundle, err := parseDataBundle(data, sum)
+	if err != nil || len(bundle.FixedHolidays) != 2 {
+		t.Fatalf("parseDataBundle = %v, %v, want 2 fixed holidays", bundle.FixedHolidays, err)
+	}
+
+	for _, fixed := range []string{
+		`{"month": 13, "day": 1, "name": "x"}`,
+		`{"month": 7, "day": 31, "name": "x"}`,
+		`{"month": 1, "day": 1, "name": ""}`,
+	} {
+		data := []byte(`{"version": "v1", "holiday_names": {"a": "b"}, "fixed_holidays": [` + fixed + `]}`)
+		sum := sha256.Sum256(data)
+		if _, err := parseDataBundle(data, hex.EncodeToString(sum[:])); err == nil || !strings.Contains(err.Error(), "bad fixed holiday") {
+			t.Errorf("fixed holiday %s: %v, want an error", fixed, err)
+		}
+	}
+}
+
+func TestLoadDataBundleFixedHolidays(t *testing.T) {
+	savedNames, savedTable, savedVersion, savedDir := holidayNames, fixedHolidayTable, dataVersion, holidayOptions.CacheDir
+	t.Cleanup(func() {
+		holidayNames, fixedHolidayTable, dataVersion, holidayOptions.CacheDir = savedNames, savedTable, savedVersion, savedDir
+	})
+	holidayOptions.CacheDir = t.TempDir()
+	path, err := dataBundleFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, sum := bundleData("v1")
+	if err := installDataBundle(path, data, sum); err != nil {
+		t.Fatal(err)
+	}
+	loadDataBundle()
+	if dataVersion != "v1" {
+		t.Fatalf("dataVersion = %q, want v1", dataVersion)
+	}
+
+	// Esfand 30 only exists in the leap year 1403.
+	for _, tt := range []struct {
+		year, want int
+	}{{1403, 2}, {1404, 1}} {
+		cal, err := shamsy.LoadHolidays(t.Context(), tt.year, fixedOptions(holidayOptions))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := len(cal.Holidays()); got != tt.want {
+			t.Errorf("%d: %d fixed holidays from the bundle, want %d", tt.year, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// defaultView reads SHAMSY_DEFAULT_VIEW and SHAMSY_DEFAULT_CALENDAR, which
+// pick what a bare "scal" prints: the month (default), a one-line "today" or
+// the current "week", in the Shamsi (default) or Gregorian calendar. They
+// only apply when scal is run without any argument or flag.
+func defaultView() (view string, gregorian bool, err error) {
+	view = strings.ToLower(os.Getenv("SHAMSY_DEFAULT_VIEW"))
+	switch view {
+	case "":
+		view = "month"
+	case "month", "today", "week":
+	default:
+		return "", false, withCode(codeConfig, fmt.Errorf("invalid SHAMSY_DEFAULT_VIEW %q (want month, today or week)", view))
+	}
+	switch calendar := strings.ToLower(os.Getenv("SHAMSY_DEFAULT_CALENDAR")); calendar {
+	case "", "shamsi":
+	case "gregorian":
+		gregorian = true
+	default:
+		return "", false, withCode(codeConfig, fmt.Errorf("invalid SHAMSY_DEFAULT_CALENDAR %q (want shamsi or gregorian)", calendar))
+	}
+	return view, gregorian, nil
+}
+
+// printTodayLine prints today's date, weekday, holiday and the working days
+// left in the month and year on a single line, cheap enough to call from a
+// shell prompt once the year's holidays are cached.
+func printTodayLine(isGregorian bool) {
+	sh := currentDate()
+	gy, gm, gd := toGregorian(sh.Year, sh.Month, sh.Day)
+	holidays, _ := fetchMonthHolidays(sh.Year, sh.Month)
+	date := rgb(yellow, shamsy.FormatShamsi(sh.Year, sh.Month, sh.Day, "YYYY/MM/DD (D MonthName YYYY)"))
+	if isGregorian {
+		date = rgb(blue, shamsy.FormatGregorian(gy, gm, gd, "YYYY/MM/DD (D MonthName YYYY)"))
+	}
+	line := fmt.Sprintf("%s %s", rgb(cyan, shamsy.WeekdayName(gy, gm, gd)), date)
+	if name, ok := holidays.IsHoliday(sh); ok {
+		line += " " + rgb(offday, holidayText(name))
+	}
+	line += " " + rgb(green, "· "+workingDaysLeftText(sh, isGregorian))
+	fmt.Println(line)
+}
+
+// printCurrentWeek prints the title and weekday header of the current month
+// followed by the grid row holding today, with the neighbouring months' days
+// filling the row when the week crosses a month boundary.
+func printCurrentWeek(isGregorian bool) {
+	sh := currentDate()
+	gy, gm, gd := toGregorian(sh.Year, sh.Month, sh.Day)
+	holidays, _ := fetchMonthHolidays(sh.Year, sh.Month)
+	opts := monthOptions{NoTrailingNewline: true, PadAdjacent: true}
+	r := renderOptions{Year: sh.Year, Month: sh.Month, Highlight: sh.Day, Holidays: holidays, monthOptions: opts}
+	row := (getFirstWeekday(sh.Year, sh.Month) + sh.Day - 1) / 7
+	if isGregorian {
+		r = renderOptions{Gregorian: true, Year: gy, Month: gm, Highlight: gd, Holidays: holidays, monthOptions: opts}
+		row = (getGregorianFirstWeekday(gy, gm) + gd - 1) / 7
+	}
+	month := captureStdout(func() { renderMonth(r) })
+	lines := strings.Split(strings.TrimSuffix(month, "\n"), "\n")
+	fmt.Println(lines[0])
+	fmt.Println(lines[1])
+	fmt.Println(lines[2+row])
+}
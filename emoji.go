@@ -0,0 +1,67 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+//go:embed data/holiday_emoji.json
+var holidayEmojiJSON []byte
+
+// holidayEmoji maps English holiday names, as in data/holiday_names.json, to
+// the icon shown with --emoji-holidays. A data bundle installed by update-data
+// replaces it.
+var holidayEmoji map[string]string
+
+// defaultHolidayEmoji marks holidays without an icon of their own.
+const defaultHolidayEmoji = "📌"
+
+// emojiHolidays prefixes holiday listings with icons.
+var emojiHolidays bool
+
+// asciiOutput leaves out the emoji of titles and holiday listings, for
+// terminals and fonts that cannot draw them.
+var asciiOutput bool
+
+func init() {
+	if err := json.Unmarshal(holidayEmojiJSON, &holidayEmoji); err != nil {
+		panic(fmt.Sprintf("invalid embedded holiday_emoji.json: %v", err))
+	}
+}
+
+// holidayIcon returns the icon of a holiday description: that of the first
+// "; "-separated event with a known icon, or defaultHolidayEmoji. Events are
+// looked up by their English name, so Persian names are translated first.
+func holidayIcon(desc string) string {
+	for _, event := range strings.Split(desc, "; ") {
+		name := event
+		if en, ok := holidayNames[normalizeHolidayName(event)]; ok {
+			name = en
+		}
+		if icon, ok := holidayEmoji[name]; ok {
+			return icon
+		}
+	}
+	return defaultHolidayEmoji
+}
+
+// withIcon prefixes s with icon and a space, unless --ascii is given. An
+// icon that most terminals draw one column wide, such as 🗓, carries a
+// second space of its own.
+func withIcon(icon, s string) string {
+	if asciiOutput {
+		return s
+	}
+	return icon + " " + s
+}
+
+// listedHolidayText is holidayText for holiday listings, prefixed with the
+// holiday's icon under --emoji-holidays.
+func listedHolidayText(desc string) string {
+	if !emojiHolidays {
+		return holidayText(desc)
+	}
+	return withIcon(holidayIcon(desc), holidayText(desc))
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// hasSymbols reports whether s contains an emoji or another pictographic
+// symbol; Persian text and punctuation such as the en dash do not count.
+func hasSymbols(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool { return r >= 0x2190 }) >= 0
+}
+
+func TestHolidayListingIcons(t *testing.T) {
+	savedEmoji, savedASCII := emojiHolidays, asciiOutput
+	t.Cleanup(func() { emojiHolidays, asciiOutput = savedEmoji, savedASCII })
+	holidays := fixedCalendar(t, 1404)
+
+	tests := []struct {
+		emoji, ascii bool
+		want         []string
+	}{
+		{false, false, []string{"📌 Holidays in this month:"}},
+		{true, false, []string{"📌 Holidays in this month:", "🌱 "}},
+		{false, true, nil},
+		{true, true, nil},
+	}
+	for _, tt := range tests {
+		emojiHolidays, asciiOutput = tt.emoji, tt.ascii
+		out := renderText(func() { printHolidaysOfMonth(1404, 1, holidays) })
+		for _, want := range tt.want {
+			if !strings.Contains(out, want) {
+				t.Errorf("emoji=%v ascii=%v: output lacks %q:\n%s", tt.emoji, tt.ascii, want, out)
+			}
+		}
+		if tt.ascii && hasSymbols(out) {
+			t.Errorf("emoji=%v ascii=%v: output has emoji:\n%s", tt.emoji, tt.ascii, out)
+		}
+		if !strings.Contains(out, "Holidays in this month:") {
+			t.Errorf("emoji=%v ascii=%v: the title is missing:\n%s", tt.emoji, tt.ascii, out)
+		}
+	}
+}
+
+func TestASCIITitles(t *testing.T) {
+	savedASCII, savedFixed := asciiOutput, fixedOnly
+	asciiOutput, fixedOnly = true, true
+	t.Cleanup(func() { asciiOutput, fixedOnly = savedASCII, savedFixed })
+	recordStatus(t)
+	commands := []struct {
+		name string
+		run  func([]string) error
+		args []string
+	}{
+		{"info", handleInfo, []string{"month", "1404", "7"}},
+		{"stats", handleStats, []string{"--weekdays", "1404"}},
+		{"fiscal", func(args []string) error { return handleFiscal(args, false) }, []string{"1404"}},
+		{"leaps", func(args []string) error { return handleLeaps(args, false) }, []string{"1400-1410"}},
+	}
+	for _, c := range commands {
+		var err error
+		out := renderText(func() { err = c.run(c.args) })
+		if err != nil {
+			t.Errorf("%s %q: %v", c.name, c.args, err)
+			continue
+		}
+		if out == "" || hasSymbols(out) {
+			t.Errorf("%s %q: output has emoji under --ascii:\n%s", c.name, c.args, out)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// maxEpochDays is the day count of the last supported date, 29 Esfand MaxYear.
+var maxEpochDays = shamsy.Date{Year: shamsy.MaxYear, Month: 12, Day: shamsy.MonthDays(shamsy.MaxYear, 12)}.EpochDays()
+
+// handleSinceEpoch prints the number of days from 1 Farvardin 1 to a Shamsi
+// date (Gregorian with -g), for use as a sortable integer key.
+func handleSinceEpoch(dateStr string, isGregorian bool) error {
+	_, _, _, date, err := parseCalendarDate(dateStr, isGregorian)
+	if err != nil {
+		return err
+	}
+	fmt.Println(date.EpochDays())
+	return nil
+}
+
+// handleFromEpoch prints the Shamsi date (Gregorian with -g) a day count of
+// --since-epoch stands for.
+func handleFromEpoch(countStr string, isGregorian bool) error {
+	n, err := strconv.Atoi(countStr)
+	if err != nil || n < 0 || n > maxEpochDays {
+		return withCode(codeInvalidArgument, fmt.Errorf("invalid day count %q: must be between 0 and %d", countStr, maxEpochDays))
+	}
+	date := shamsy.DateFromEpochDays(n)
+	if isGregorian {
+		g := gregorianOf(date)
+		fmt.Println(shamsy.FormatGregorian(g.Year, g.Month, g.Day, "YYYY/MM/DD"))
+		return nil
+	}
+	fmt.Println(date)
+	return nil
+}
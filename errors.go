@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Error codes reported in the "code" field of JSON errors. They are part of
+// the output format and must not change.
+const (
+	codeInvalidDate     = "invalid_date"         // malformed or nonexistent date
+	codeInvalidArgument = "invalid_argument"     // bad year, month or option value
+	codeUsage           = "usage"                // wrong number or combination of arguments
+	codeHolidays        = "holidays_unavailable" // holidays could not be loaded
+	codeConfig          = "config_error"         // unreadable or invalid config file
+	codeError           = "error"                // anything else
+)
+
+// codedError attaches one of the error codes to an error.
+type codedError struct {
+	code string
+	err  error
+}
+
+func (e *codedError) Error() string { return e.err.Error() }
+func (e *codedError) Unwrap() error { return e.err }
+
+// withCode tags err with code; nil stays nil.
+func withCode(code string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{code: code, err: err}
+}
+
+// errorCode returns the code err was tagged with, or codeError.
+func errorCode(err error) string {
+	var ce *codedError
+	if errors.As(err, &ce) {
+		return ce.code
+	}
+	return codeError
+}
+
+// fail reports err and exits with status 1. With --json the error goes to
+// stdout as {"error": {"code": ..., "message": ...}} and stderr stays clean.
+func fail(err error) {
+	abortOutput()
+	if jsonOutput {
+		out := map[string]map[string]string{
+			"error": {"code": errorCode(err), "message": err.Error()},
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(out)
+	} else {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+	os.Exit(1)
+}
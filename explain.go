@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// gregorianDayOfYear returns the 1-based ordinal of a Gregorian date in its year.
+func gregorianDayOfYear(gy, gm, gd int) int {
+	doy := gd
+	for m := 1; m < gm; m++ {
+		doy += gregorianMonthDays(gy, m)
+	}
+	return doy
+}
+
+// shamsyMonthFromDayOfYear splits a Shamsi day-of-year into month and day
+// and describes the step: the first six months have 31 days, the next five 30.
+func shamsyMonthFromDayOfYear(doy int) (int, int, string) {
+	if doy <= 186 {
+		jm, jd := (doy-1)/31+1, (doy-1)%31+1
+		return jm, jd, fmt.Sprintf("%d = %d×31 + %d (months 1-6 have 31 days)", doy, jm-1, jd)
+	}
+	rest := doy - 186
+	jm, jd := (rest-1)/30+7, (rest-1)%30+1
+	return jm, jd, fmt.Sprintf("%d = 186 + %d×30 + %d (months 7-12 have 30 days)", doy, jm-7, jd)
+}
+
+func leapLabel(leap bool) string {
+	if leap {
+		return "leap"
+	}
+	return "common"
+}
+
+// handleExplain prints the intermediate values of a conversion so that
+// conversion bugs can be reported with concrete numbers.
+func handleExplain(dateStr string, isGregorian bool) error {
+	gy, gm, gd, date, err := parseCalendarDate(dateStr, isGregorian)
+	if err != nil {
+		return err
+	}
+	year, month, day := date.Year, date.Month, date.Day
+	if isGregorian {
+		year, month, day = gy, gm, gd
+	}
+	line := func(label, value string) {
+		fmt.Printf("%s: %s\n", rgb(green, fmt.Sprintf("%-24s", label)), value)
+	}
+	fmt.Println(rgb(cyan, strings.Repeat("=", 60)))
+	cycle := func(jy int, t shamsy.Trace) {
+		line("33-year cycle", rgb(cyan, fmt.Sprintf("%d + 1595 = 33×%d + %d", jy, t.Cycle, t.CycleYear)))
+		line("Leap days before year", rgb(cyan, fmt.Sprintf("8×%d + (%d+3)/4 = %d", t.Cycle, t.CycleYear, t.LeapDays)))
+	}
+	epochs := func(t shamsy.Trace) {
+		line("Days since 1 Farvardin 1", rgb(cyan, fmt.Sprint(t.ShamsiEpochDays)))
+		line("Days since 1 January 1", rgb(cyan, fmt.Sprint(t.GregorianEpochDays)))
+	}
+	if isGregorian {
+		fmt.Println(rgb(purple, withIcon("🔍", "Explaining Gregorian to Shamsi")))
+		fmt.Println(rgb(cyan, strings.Repeat("-", 60)))
+		jy, jm, jd, t := shamsy.FromGregorianWithTrace(year, month, day)
+		ny, nm, nd := toGregorian(jy, 1, 1)
+		line("Input (Gregorian)", rgb(blue, shamsy.FormatGregorian(year, month, day, "YYYY/MM/DD")))
+		line("Day number (JDN)", rgb(cyan, fmt.Sprint(t.JDN)))
+		epochs(t)
+		line("Gregorian day of year", rgb(cyan, fmt.Sprintf("%d (%d is %s)", gregorianDayOfYear(year, month, day), year, leapLabel(isGregorianLeapYear(year)))))
+		// Nowruz of Shamsi year Y falls in March of Gregorian year Y+621.
+		if t.BeforeNowruz {
+			line("Shamsi year", rgb(cyan, fmt.Sprintf("%d - 622 = %d, before Nowruz of %d (%s)", year, jy, jy+1, leapLabel(shamsy.IsLeapYear(jy)))))
+		} else {
+			line("Shamsi year", rgb(cyan, fmt.Sprintf("%d - 621 = %d (%s)", year, jy, leapLabel(shamsy.IsLeapYear(jy)))))
+		}
+		cycle(jy, t)
+		line("1 Farvardin", rgb(cyan, fmt.Sprintf("%s (JDN %d)", shamsy.FormatGregorian(ny, nm, nd, "YYYY/MM/DD"), t.NowruzJDN)))
+		line("Days since Nowruz", rgb(cyan, fmt.Sprint(t.DayOfYear-1)))
+		_, _, step := shamsyMonthFromDayOfYear(t.DayOfYear)
+		line("Shamsi day of year", rgb(cyan, step))
+		line("Output (Shamsi)", rgb(yellow, shamsy.FormatShamsi(jy, jm, jd, "YYYY/MM/DD")))
+	} else {
+		fmt.Println(rgb(purple, withIcon("🔍", "Explaining Shamsi to Gregorian")))
+		fmt.Println(rgb(cyan, strings.Repeat("-", 60)))
+		gy, gm, gd, t := shamsy.ToGregorianWithTrace(year, month, day)
+		ny, nm, nd := toGregorian(year, 1, 1)
+		_, _, step := shamsyMonthFromDayOfYear(t.DayOfYear)
+		line("Input (Shamsi)", rgb(yellow, shamsy.FormatShamsi(year, month, day, "YYYY/MM/DD")))
+		line("Shamsi year", rgb(cyan, fmt.Sprintf("%d (%s)", year, leapLabel(shamsy.IsLeapYear(year)))))
+		cycle(year, t)
+		line("Shamsi day of year", rgb(cyan, step))
+		line("1 Farvardin", rgb(cyan, fmt.Sprintf("%s (JDN %d)", shamsy.FormatGregorian(ny, nm, nd, "YYYY/MM/DD"), t.NowruzJDN)))
+		line("Day number (JDN)", rgb(cyan, fmt.Sprintf("%d + %d = %d", t.NowruzJDN, t.DayOfYear-1, t.JDN)))
+		epochs(t)
+		line("Output (Gregorian)", rgb(blue, shamsy.FormatGregorian(gy, gm, gd, "YYYY/MM/DD")))
+		line("Gregorian day of year", rgb(cyan, fmt.Sprintf("%d (%d is %s)", gregorianDayOfYear(gy, gm, gd), gy, leapLabel(isGregorianLeapYear(gy)))))
+	}
+	fmt.Println(rgb(cyan, strings.Repeat("=", 60)))
+	return nil
+}
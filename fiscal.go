@@ -0,0 +1,133 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// shamsyDayOfYear returns the 1-based ordinal of a Shamsi date in its year.
+func shamsyDayOfYear(jm, jd int) int {
+	if jm <= 6 {
+		return (jm-1)*31 + jd
+	}
+	return 186 + (jm-7)*30 + jd
+}
+
+// fiscalQuarter is one quarter of a Shamsi fiscal year. Q1 starts on
+// 1 Farvardin and every quarter spans three whole months.
+type fiscalQuarter struct {
+	Number      int
+	FirstMonth  int
+	LastMonth   int
+	Days        int
+	WorkingDays int
+}
+
+func fiscalQuarters(jy int, holidays *shamsy.HolidayCalendar) []fiscalQuarter {
+	quarters := make([]fiscalQuarter, 4)
+	for q := range quarters {
+		fq := fiscalQuarter{Number: q + 1, FirstMonth: q*3 + 1, LastMonth: q*3 + 3}
+		for m := fq.FirstMonth; m <= fq.LastMonth; m++ {
+			fq.Days += shamsy.MonthDays(jy, m)
+		}
+		fq.WorkingDays = holidays.WorkingDays(shamsy.Date{Year: jy, Month: fq.FirstMonth, Day: 1},
+			shamsy.Date{Year: jy, Month: fq.LastMonth, Day: shamsy.MonthDays(jy, fq.LastMonth)})
+		quarters[q] = fq
+	}
+	return quarters
+}
+
+// shamsySeasons names the fiscal quarters after the seasons they span.
+var shamsySeasons = []string{"Bahar", "Tabestan", "Paeez", "Zemestan"}
+
+// printQuarterGrid prints the year view as four labeled quarter rows of three
+// months each, following the fiscal year.
+func printQuarterGrid(opts monthOptions, renderMonth func(m int, opts monthOptions)) {
+	for q := 0; q < 4; q++ {
+		fmt.Println(rgb(purple, fmt.Sprintf("Q%d %s", q+1, shamsySeasons[q])))
+		printYearRow(q*3+1, 3, opts, renderMonth)
+	}
+}
+
+// handleFiscal implements "fiscal [year] [--date DATE]".
+func handleFiscal(args []string, isGregorian bool) error {
+	fs := flag.NewFlagSet("fiscal", flag.ContinueOnError)
+	dateStr := fs.String("date", "", "Report the fiscal quarter of this date")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 1 {
+		return withCode(codeUsage, fmt.Errorf("usage: shamsy-calendar fiscal [year] [--date DATE]"))
+	}
+
+	// The date, when given, is always resolved to Shamsi.
+	var dy, dm, dd int
+	if *dateStr != "" {
+		_, _, _, date, err := parseCalendarDate(*dateStr, isGregorian)
+		if err != nil {
+			return err
+		}
+		dy, dm, dd = date.Year, date.Month, date.Day
+	}
+
+	var jy int
+	switch {
+	case len(positional) == 1:
+		jy, err = strconv.Atoi(positional[0])
+		if err != nil || !shamsy.InRange(jy) {
+			return withCode(codeInvalidArgument, fmt.Errorf("invalid year argument %q", positional[0]))
+		}
+		if *dateStr != "" && dy != jy {
+			return withCode(codeInvalidArgument, fmt.Errorf("date %d/%02d/%02d is not in fiscal year %d", dy, dm, dd, jy))
+		}
+	case *dateStr != "":
+		jy = dy
+	default:
+		now := currentTime()
+		jy, _, _ = fromGregorian(now.Year(), int(now.Month()), now.Day())
+	}
+
+	holidays, err := fetchHolidays(jy)
+	if err != nil {
+		return err
+	}
+	quarters := fiscalQuarters(jy, holidays)
+
+	fmt.Println(rgb(cyan, strings.Repeat("=", 60)))
+	fmt.Println(rgb(purple, withIcon("📊", fmt.Sprintf("Fiscal year %d", jy))))
+	fmt.Println(rgb(cyan, strings.Repeat("-", 60)))
+	for _, q := range quarters {
+		last := shamsy.MonthDays(jy, q.LastMonth)
+		gsy, gsm, gsd := toGregorian(jy, q.FirstMonth, 1)
+		gey, gem, ged := toGregorian(jy, q.LastMonth, last)
+		fmt.Printf("%s %s\n", rgb(green, fmt.Sprintf("Q%d", q.Number)),
+			rgb(yellow, fmt.Sprintf("%s–%s", shamsyMonths[q.FirstMonth-1], shamsyMonths[q.LastMonth-1])))
+		fmt.Printf("   %s: %s\n", rgb(green, "Shamsi   "),
+			rgb(yellow, shamsy.FormatShamsi(jy, q.FirstMonth, 1, "YYYY/MM/DD")+" – "+shamsy.FormatShamsi(jy, q.LastMonth, last, "YYYY/MM/DD")))
+		fmt.Printf("   %s: %s\n", rgb(green, "Gregorian"),
+			rgb(blue, shamsy.FormatGregorian(gsy, gsm, gsd, "YYYY/MM/DD")+" – "+shamsy.FormatGregorian(gey, gem, ged, "YYYY/MM/DD")))
+		fmt.Printf("   %s: %s\n", rgb(green, "Days     "),
+			rgb(cyan, fmt.Sprintf("%d (%d working)", q.Days, q.WorkingDays)))
+	}
+	printFixedOnlyNote()
+
+	if *dateStr != "" {
+		q := quarters[(dm-1)/3]
+		start := shamsyDayOfYear(q.FirstMonth, 1)
+		end := shamsyDayOfYear(q.LastMonth, shamsy.MonthDays(jy, q.LastMonth))
+		today := shamsyDayOfYear(dm, dd)
+		fmt.Println(rgb(cyan, strings.Repeat("-", 60)))
+		fmt.Printf("%s: %s\n", rgb(green, "Date"),
+			rgb(yellow, shamsy.FormatShamsi(dy, dm, dd, "YYYY/MM/DD - D MonthName YYYY")))
+		fmt.Printf("%s: %s\n", rgb(green, "Quarter"), rgb(cyan, fmt.Sprintf("Q%d", q.Number)))
+		fmt.Printf("%s: %s\n", rgb(green, "Elapsed"), rgb(cyan, fmt.Sprintf("%d of %d days", today-start+1, q.Days)))
+		fmt.Printf("%s: %s\n", rgb(green, "Remaining"), rgb(cyan, fmt.Sprintf("%d days", end-today)))
+	}
+	fmt.Println(rgb(cyan, strings.Repeat("=", 60)))
+	return nil
+}
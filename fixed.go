@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// fixedOnly answers holiday questions from the built-in table of fixed
+// national holidays instead of the API and the cache.
+var fixedOnly bool
+
+// fixedHolidayTable replaces the built-in table of fixed holidays when the
+// data bundle has one.
+var fixedHolidayTable []shamsy.FixedHoliday
+
+// fixedProvider supplies the fixed holidays, from the data bundle if it has
+// them.
+func fixedProvider() shamsy.FixedProvider {
+	return shamsy.FixedProvider{Table: fixedHolidayTable}
+}
+
+// fixedOptions switches holiday loading to the built-in table. Nothing is
+// read from or written to the holiday cache, so results do not depend on what
+// an earlier run fetched, and nothing is fetched, so no progress is shown.
+func fixedOptions(opts shamsy.Options) shamsy.Options {
+	opts.Providers = []shamsy.Provider{fixedProvider()}
+	opts.NoCache = true
+	opts.Offline = false
+	opts.Progress = nil
+	return opts
+}
+
+// holidayClasses names the kinds of days treated as off days, for JSON
+// output: Fridays always, fixed national holidays, movable religious holidays
+// unless --fixed-only is given, and the config rules and observed days when
+// in use.
+func holidayClasses() []string {
+	classes := []string{"fridays", "fixed"}
+	if !fixedOnly {
+		classes = append(classes, "movable")
+	}
+	if len(holidayRules) > 0 {
+		classes = append(classes, "rules")
+	}
+	if observedMode {
+		classes = append(classes, "observed")
+	}
+	return classes
+}
+
+// printFixedOnlyNote reminds that holiday listings and counts are incomplete
+// with --fixed-only.
+func printFixedOnlyNote() {
+	if fixedOnly {
+		fmt.Println(rgb(yellow, "Note: fixed holidays only; movable religious holidays are excluded."))
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+import "testing"
+
+func TestFixedOnlyShowsNoProgress(t *testing.T) {
+	saved := fixedOnly
+	fixedOnly = true
+	defer func() { fixedOnly = saved }()
+	rec := recordStatus(t)
+	cal, err := fetchHolidays(1404)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cal.Holidays()) == 0 {
+		t.Error("no fixed holidays loaded")
+	}
+	if len(rec.started) != 0 {
+		t.Errorf("status messages %q with --fixed-only, want none", rec.started)
+	}
+}
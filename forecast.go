@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// lunarHoliday is an official holiday on a fixed date of the Hijri calendar.
+// A Day of 0 is the last day of the month, which has 29 or 30 days.
+type lunarHoliday struct {
+	Month, Day int
+	Name       string
+}
+
+// lunarHolidays are the official holidays that follow the Hijri calendar. The
+// names match the ones used by the pnldev.com API so that translations apply.
+var lunarHolidays = []lunarHoliday{
+	{1, 9, "تاسوعای حسینی"},
+	{1, 10, "عاشورای حسینی"},
+	{2, 20, "اربعین حسینی"},
+	{2, 28, "رحلت رسول اکرم؛شهادت امام حسن مجتبی [ ع ]"},
+	{2, 0, "شهادت امام رضا [ ع ]"},
+	{3, 8, "شهادت امام حسن عسکری [ ع ]"},
+	{3, 17, "میلاد رسول اکرم و امام جعفر صادق [ ع ]"},
+	{6, 3, "شهادت حضرت فاطمه زهرا [ س ]"},
+	{7, 13, "ولادت امام علی [ ع ] و روز پدر"},
+	{7, 27, "مبعث رسول اکرم [ ص ]"},
+	{8, 15, "ولادت حضرت قائم [ عج ] و جشن نیمه شعبان"},
+	{9, 21, "شهادت امام علی [ ع ]"},
+	{10, 1, "عید سعید فطر"},
+	{10, 2, "تعطیل به مناسبت عید سعید فطر"},
+	{10, 25, "شهادت امام جعفر صادق [ ع ]"},
+	{12, 10, "عید سعید قربان"},
+	{12, 18, "عید سعید غدیر خم"},
+}
+
+// hijriToJDN converts a date of the tabular Islamic calendar to a Julian Day
+// Number; it is the inverse of hijriFromJDN.
+func hijriToJDN(y, m, d int) int {
+	return d + 30*(m-1) - (m-1)/2 + (y-1)*354 + (3+11*y)/30 + 1948439
+}
+
+// dateFromJDN returns the Shamsi date of a Julian Day Number.
+func dateFromJDN(jdn int) shamsy.Date {
+	return shamsy.DateFromEpochDays(jdn - shamsy.ShamsyJDN(shamsy.MinYear, 1, 1))
+}
+
+// forecastLunarHolidays estimates the lunar holidays of Shamsi year jy from
+// the tabular Hijri calendar, which can be a day or two off the sighted
+// dates the official calendar uses. The result is sorted by date.
+func forecastLunarHolidays(jy int) []shamsy.Holiday {
+	first := dateJDN(shamsy.Date{Year: jy, Month: 1, Day: 1})
+	last := dateJDN(shamsy.Date{Year: jy, Month: 12, Day: shamsy.MonthDays(jy, 12)})
+	fromYear, _, _ := hijriFromJDN(first)
+	toYear, _, _ := hijriFromJDN(last)
+	var holidays []shamsy.Holiday
+	for hy := fromYear; hy <= toYear; hy++ {
+		for _, l := range lunarHolidays {
+			jdn := hijriToJDN(hy, l.Month, l.Day)
+			if l.Day == 0 {
+				jdn = hijriToJDN(hy, l.Month+1, 1) - 1
+			}
+			if jdn >= first && jdn <= last {
+				holidays = append(holidays, shamsy.Holiday{Date: dateFromJDN(jdn), Name: l.Name})
+			}
+		}
+	}
+	sort.Slice(holidays, func(i, j int) bool { return holidays[i].Date.Compare(holidays[j].Date) < 0 })
+	return holidays
+}
+
+// forecastProvider supplies the fixed national holidays plus the estimated
+// lunar ones. A lunar holiday falling on a fixed one is left out.
+type forecastProvider struct{}
+
+// Holidays implements shamsy.Provider.
+func (forecastProvider) Holidays(ctx context.Context, year int) ([]shamsy.Holiday, error) {
+	holidays, err := fixedProvider().Holidays(ctx, year)
+	if err != nil {
+		return nil, err
+	}
+	fixed := make(map[shamsy.Date]bool, len(holidays))
+	for _, h := range holidays {
+		fixed[h.Date] = true
+	}
+	for _, h := range forecastLunarHolidays(year) {
+		if !fixed[h.Date] {
+			holidays = append(holidays, h)
+		}
+	}
+	sort.Slice(holidays, func(i, j int) bool { return holidays[i].Date.Compare(holidays[j].Date) < 0 })
+	return holidays, nil
+}
+
+// forecastEstimates returns the days of Shamsi year jy whose forecast
+// holiday is an estimate: the lunar ones that do not fall on a fixed one.
+func forecastEstimates(jy int, holidays []shamsy.Holiday) map[shamsy.Date]bool {
+	lunar := map[shamsy.Date]string{}
+	for _, h := range forecastLunarHolidays(jy) {
+		lunar[h.Date] = h.Name
+	}
+	estimates := map[shamsy.Date]bool{}
+	for _, h := range holidays {
+		if name, ok := lunar[h.Date]; ok && name == h.Name {
+			estimates[h.Date] = true
+		}
+	}
+	return estimates
+}
+
+// forecastMode falls back to forecastCalendar for years whose holidays cannot
+// be loaded, e.g. future years the API has not published yet.
+var forecastMode bool
+
+// estimated holds the days whose holiday comes from a forecast, which the
+// grid draws in estimatedColor.
+var estimated = map[shamsy.Date]bool{}
+
+// estimatedColor marks estimated holidays in the grid.
+var estimatedColor = Color{255, 140, 140}
+
+// forecastCalendar returns the provisional holidays of Shamsi year jy and
+// records the estimated ones in estimated.
+func forecastCalendar(jy int) (*shamsy.HolidayCalendar, error) {
+	opts := fixedOptions(holidayOptions)
+	opts.Observed = observedMode
+	opts.Providers = []shamsy.Provider{forecastProvider{}}
+	cal, err := shamsy.LoadHolidays(fetchContext, jy, opts)
+	if err != nil {
+		return nil, err
+	}
+	for d := range forecastEstimates(jy, cal.Holidays()) {
+		estimated[d] = true
+	}
+	return cal.WithHolidays(ruleHolidays(jy)), nil
+}
+
+// printEstimateNote reminds that some holidays shown are estimates.
+func printEstimateNote() {
+	if len(estimated) > 0 {
+		fmt.Println(rgb(yellow, "Note: lunar holidays are estimated and may be 1–2 days off the official dates."))
+	}
+}
+
+// forecastJSON is one holiday of forecast --json.
+type forecastJSON struct {
+	Shamsi    string `json:"shamsi"`
+	Gregorian string `json:"gregorian"`
+	Weekday   string `json:"weekday"`
+	Name      string `json:"name"`
+	Estimated bool   `json:"estimated"`
+}
+
+// handleForecast implements "forecast YEAR [--json]": the provisional
+// holidays of a Shamsi year, the fixed national ones and the lunar ones
+// estimated from the tabular Hijri calendar.
+func handleForecast(args []string) error {
+	fs := flag.NewFlagSet("forecast", flag.ContinueOnError)
+	fs.BoolVar(&jsonOutput, "json", jsonOutput, "Print JSON")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return withCode(codeUsage, fmt.Errorf("usage: shamsy-calendar forecast YEAR [--json]"))
+	}
+	jy, err := strconv.Atoi(positional[0])
+	if err != nil || !shamsy.InRange(jy) {
+		return withCode(codeInvalidArgument, fmt.Errorf("invalid year argument %q (supported: %s)", positional[0], supportedRange(false)))
+	}
+	holidays, err := forecastProvider{}.Holidays(fetchContext, jy)
+	if err != nil {
+		return err
+	}
+	estimates := forecastEstimates(jy, holidays)
+
+	if jsonOutput {
+		rows := make([]forecastJSON, 0, len(holidays))
+		for _, h := range holidays {
+			g := gregorianOf(h.Date)
+			rows = append(rows, forecastJSON{
+				Shamsi:    h.Date.String(),
+				Gregorian: shamsy.FormatGregorian(g.Year, g.Month, g.Day, "YYYY-MM-DD"),
+				Weekday:   g.DayWeek,
+				Name:      holidayText(h.Name),
+				Estimated: estimates[h.Date],
+			})
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return checkStdout(enc.Encode(rows))
+	}
+	fmt.Println(rgb(cyan, strings.Repeat("=", 60)))
+	fmt.Println(rgb(purple, withIcon("🔮", fmt.Sprintf("Holiday forecast for %d", jy))))
+	fmt.Println(rgb(cyan, strings.Repeat("-", 60)))
+	for _, h := range holidays {
+		g := gregorianOf(h.Date)
+		name, color := holidayText(h.Name), offday
+		if estimates[h.Date] {
+			name, color = name+" (estimated ±1–2 days)", estimatedColor
+		}
+		fmt.Printf("- %s  %s  %s: %s\n",
+			rgb(yellow, h.Date.String()),
+			rgb(blue, shamsy.FormatGregorian(g.Year, g.Month, g.Day, "YYYY/MM/DD")),
+			rgb(cyan, fmt.Sprintf("%-9s", g.DayWeek)),
+			rgb(color, name))
+	}
+	fmt.Println(rgb(cyan, strings.Repeat("-", 60)))
+	fmt.Printf("%s: %s\n", rgb(green, "Holidays"), rgb(offday, fmt.Sprintf("%d (%d estimated)", len(holidays), len(estimates))))
+	fmt.Println(rgb(yellow, "Note: lunar holidays are projected from the tabular Hijri calendar and may be"))
+	fmt.Println(rgb(yellow, "1–2 days off the official dates, which follow moon sightings."))
+	fmt.Println(rgb(cyan, strings.Repeat("=", 60)))
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// hijriFromJDN converts a Julian Day Number to the tabular (arithmetical)
+// Islamic calendar. Official Hijri dates follow moon sightings and may differ
+// from it by a day or two.
+func hijriFromJDN(jdn int) (int, int, int) {
+	l := jdn - 1948440 + 10632
+	n := (l - 1) / 10631
+	l = l - 10631*n + 354
+	j := ((10985-l)/5316)*((50*l)/17719) + (l/5670)*((43*l)/15238)
+	l = l - ((30-j)/15)*((17719*j)/50) - (j/16)*((15238*j)/43) + 29
+	m := (24 * l) / 709
+	d := l - (709*m)/24
+	y := 30*n + j - 30
+	return y, m, d
+}
+
+// dateFormat is one representation selectable with --formats.
+type dateFormat struct {
+	Label  string
+	Format func(gy, gm, gd int) interface{}
+}
+
+// dateFormats are the tokens accepted by --formats.
+var dateFormats = map[string]dateFormat{
+	"iso": {"ISO 8601 (Gregorian)", func(gy, gm, gd int) interface{} {
+		return shamsy.FormatGregorian(gy, gm, gd, "YYYY-MM-DD")
+	}},
+	"shamsi": {"Shamsi", func(gy, gm, gd int) interface{} {
+		jy, jm, jd := fromGregorian(gy, gm, gd)
+		return shamsy.FormatShamsi(jy, jm, jd, "YYYY/MM/DD")
+	}},
+	"hijri": {"Hijri (tabular)", func(gy, gm, gd int) interface{} {
+		hy, hm, hd := hijriFromJDN(shamsy.GregorianJDN(gy, gm, gd))
+		return fmt.Sprintf("%04d/%02d/%02d", hy, hm, hd)
+	}},
+	"jdn": {"Julian Day Number", func(gy, gm, gd int) interface{} {
+		return shamsy.GregorianJDN(gy, gm, gd)
+	}},
+	"epoch": {"Days since epoch", func(gy, gm, gd int) interface{} {
+		return dateFromGregorian(gy, gm, gd).EpochDays()
+	}},
+	"gweek": {"ISO week (Y/W/D)", func(gy, gm, gd int) interface{} {
+		return gweekDate(gy, gm, gd)
+	}},
+}
+
+// handleFormats prints a date in each of the comma-separated formats, one
+// labeled line per format or a single JSON object with --json.
+func handleFormats(dateStr string, isGregorian bool, formats string) error {
+	gy, gm, gd, _, err := parseCalendarDate(dateStr, isGregorian)
+	if err != nil {
+		return err
+	}
+
+	var tokens []string
+	for _, token := range strings.Split(formats, ",") {
+		token = strings.ToLower(strings.TrimSpace(token))
+		if _, ok := dateFormats[token]; !ok {
+			return withCode(codeInvalidArgument, fmt.Errorf("unknown format %q (available: iso, shamsi, hijri, jdn, epoch, gweek)", token))
+		}
+		tokens = append(tokens, token)
+	}
+
+	if jsonOutput {
+		values := make(map[string]interface{}, len(tokens))
+		for _, token := range tokens {
+			values[token] = dateFormats[token].Format(gy, gm, gd)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return checkStdout(enc.Encode(values))
+	}
+	for _, token := range tokens {
+		f := dateFormats[token]
+		fmt.Printf("%s: %s\n", rgb(green, fmt.Sprintf("%-20s", f.Label)), rgb(cyan, fmt.Sprint(f.Format(gy, gm, gd))))
+	}
+	return nil
+}
@@ -1,12 +1,14 @@
-module main.go
+module github.com/Aria-Ghojavand/shamsy-calendar
 
 go 1.24.2
 
-require github.com/schollz/progressbar/v3 v3.18.0
+require (
+	github.com/rivo/uniseg v0.4.7
+	github.com/schollz/progressbar/v3 v3.18.0
+	golang.org/x/term v0.28.0
+)
 
 require (
 	github.com/mitchellh/colorstring v0.0.0-20190213212951-d06e56a500db // indirect
-	github.com/rivo/uniseg v0.4.7 // indirect
 	golang.org/x/sys v0.29.0 // indirect
-	golang.org/x/term v0.28.0 // indirect
 )
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// halfDayColor marks the weekdays chosen with --half-day, e.g. Thursdays
+// that are only worked until noon.
+var halfDayColor = Color{255, 120, 50}
+
+// halfDays is the set of weekdays colored as half-days.
+var halfDays = map[time.Weekday]bool{}
+
+// parseHalfDays parses the comma-separated --half-day weekdays.
+func parseHalfDays(list string) (map[time.Weekday]bool, error) {
+	days := map[time.Weekday]bool{}
+	for _, entry := range strings.Split(list, ",") {
+		wd, ok := parseWeekday(entry)
+		if !ok {
+			return nil, withCode(codeInvalidArgument, fmt.Errorf("invalid weekday %q in --half-day", entry))
+		}
+		days[wd] = true
+	}
+	return days, nil
+}
+
+// parseWeekday parses an English weekday name or a prefix of at least two
+// letters ("th", "thu", "thursday"), or a Shamsi column label such as "Pa".
+func parseWeekday(s string) (time.Weekday, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		name := strings.ToLower(wd.String())
+		label := strings.ToLower(weekDays[goToshamsyWeekday[wd]])
+		if (len(s) >= 2 && strings.HasPrefix(name, s)) || s == label {
+			return wd, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// noHighlight is the highlight argument of the month renderers for a month
+// without a highlighted day. Any other value is a day of the month.
+const noHighlight = 0
+
+// checkHighlight warns about a highlight that is neither noHighlight nor a
+// day of a month with days days, since it would silently match nothing.
+func checkHighlight(highlight, days int) {
+	if highlight != noHighlight && (highlight < 1 || highlight > days) {
+		status.Warn(fmt.Sprintf("highlighted day %d is not in the month (1-%d)", highlight, days))
+	}
+}
+
+// highlightOverride is the day given with --highlight, replacing today's in
+// a month view; overrideHighlight tells whether it was given. "none" sets it
+// to noHighlight.
+var (
+	highlightOverride int
+	overrideHighlight bool
+)
+
+// parseHighlight parses --highlight: a day number or "none".
+func parseHighlight(s string) error {
+	if s == "" {
+		return nil
+	}
+	overrideHighlight = true
+	if strings.EqualFold(s, "none") {
+		highlightOverride = noHighlight
+		return nil
+	}
+	d, err := strconv.Atoi(s)
+	if err != nil || d < 1 {
+		return withCode(codeInvalidArgument, fmt.Errorf("invalid --highlight %q: must be a day of the month or \"none\"", s))
+	}
+	highlightOverride = d
+	return nil
+}
+
+// monthHighlight returns the day to highlight in the month title of days
+// days: the --highlight day when given, otherwise def. A --highlight day past
+// the end of the month is an error.
+func monthHighlight(def, days int, title string) (int, error) {
+	if !overrideHighlight {
+		return def, nil
+	}
+	if highlightOverride > days {
+		return 0, withCode(codeInvalidArgument, fmt.Errorf("invalid --highlight %d: %s has %d days", highlightOverride, title, days))
+	}
+	return highlightOverride, nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// historyLimit is how many recent conversions "history" lists.
+const historyLimit = 20
+
+// noHistory disables recording for one invocation even when the config
+// enables the history.
+var noHistory bool
+
+// historyEntry is one recorded -c conversion.
+type historyEntry struct {
+	Time      time.Time
+	Gregorian bool // the input date was Gregorian (-g)
+	Date      string
+}
+
+// historyFile returns the history location:
+// $XDG_STATE_HOME/shamsy_calendar/history, or ~/.local/state/shamsy_calendar/history.
+func historyFile() (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "shamsy_calendar", "history"), nil
+}
+
+// recordConversion appends a conversion to the history when the config
+// enables it. Failures only produce a warning with --verbose, so recording
+// never fails the conversion itself.
+func recordConversion(dateStr string, isGregorian bool) {
+	if !userConfig.History || noHistory {
+		return
+	}
+	calendar := "shamsi"
+	if isGregorian {
+		calendar = "gregorian"
+	}
+	line := fmt.Sprintf("%s\t%s\t%s\n", time.Now().Format(time.RFC3339), calendar, dateStr)
+	path, err := historyFile()
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+	}
+	var f *os.File
+	if err == nil {
+		f, err = os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	}
+	if err == nil {
+		_, err = f.WriteString(line)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil && verbose {
+		status.Warn(fmt.Sprintf("failed to record history: %v", err))
+	}
+}
+
+// readHistory returns the recorded conversions, oldest first. Malformed
+// lines are skipped.
+func readHistory() ([]historyEntry, error) {
+	path, err := historyFile()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var entries []historyEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) != 3 {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, fields[0])
+		if err != nil {
+			continue
+		}
+		entries = append(entries, historyEntry{Time: t, Gregorian: fields[1] == "gregorian", Date: fields[2]})
+	}
+	return entries, scanner.Err()
+}
+
+// handleHistory implements "history" and "history run N". Entries are
+// numbered from the oldest, so a number keeps referring to the same
+// conversion as new ones are added.
+func handleHistory(args []string) error {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	entries, err := readHistory()
+	if err != nil {
+		return fmt.Errorf("failed to read history: %v", err)
+	}
+	switch {
+	case len(positional) == 0:
+		if len(entries) == 0 {
+			fmt.Println("No conversions recorded. Set \"history\": true in the config file to record them.")
+			return nil
+		}
+		start := len(entries) - historyLimit
+		if start < 0 {
+			start = 0
+		}
+		for i := start; i < len(entries); i++ {
+			e := entries[i]
+			calendar := "Shamsi   "
+			if e.Gregorian {
+				calendar = "Gregorian"
+			}
+			fmt.Printf("%s  %s  %s  %s\n", rgb(green, fmt.Sprintf("%4d", i+1)),
+				rgb(cyan, e.Time.Local().Format("2006-01-02 15:04")), rgb(purple, calendar), rgb(yellow, e.Date))
+		}
+		return nil
+	case len(positional) == 2 && positional[0] == "run":
+		n, err := strconv.Atoi(positional[1])
+		if err != nil || n < 1 || n > len(entries) {
+			return withCode(codeInvalidArgument, fmt.Errorf("invalid history entry %q: %d conversions are recorded", positional[1], len(entries)))
+		}
+		e := entries[n-1]
+		return handleConvertDate(e.Date, e.Gregorian)
+	}
+	return withCode(codeUsage, fmt.Errorf("usage: shamsy-calendar history [run N]"))
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// holidayJSON is one entry of --show-holidays --json. Date is the Shamsi
+// date (YYYY/MM/DD) and Gregorian the Gregorian one (YYYY-MM-DD).
+type holidayJSON struct {
+	Date      string `json:"date"`
+	Gregorian string `json:"gregorian"`
+	Weekday   string `json:"weekday"`
+	Event     string `json:"event"`
+}
+
+func newHolidayJSON(date shamsy.Date, gy, gm, gd int, name string) holidayJSON {
+	return holidayJSON{
+		Date:      date.String(),
+		Gregorian: shamsy.FormatGregorian(gy, gm, gd, "YYYY-MM-DD"),
+		Weekday:   shamsy.WeekdayName(gy, gm, gd),
+		Event:     holidayText(name),
+	}
+}
+
+// shamsyMonthHolidaysJSON lists the holidays of a Shamsi month by date.
+func shamsyMonthHolidaysJSON(jy, jm int, holidays *shamsy.HolidayCalendar) []holidayJSON {
+	entries := []holidayJSON{}
+	for _, h := range holidays.HolidaysIn(jy, jm) {
+		g := gregorianOf(h.Date)
+		entries = append(entries, newHolidayJSON(h.Date, g.Year, g.Month, g.Day, h.Name))
+	}
+	return entries
+}
+
+// gregorianMonthHolidaysJSON lists the official holidays of a Gregorian month
+// by date. International observances are not days off and are left out.
+func gregorianMonthHolidaysJSON(year, month int, shamsyHolidays *shamsy.HolidayCalendar) []holidayJSON {
+	entries := []holidayJSON{}
+	for d := 1; d <= gregorianMonthDays(year, month); d++ {
+		if name, ok := shamsyHolidays.IsGregorianHoliday(year, month, d); ok {
+			entries = append(entries, newHolidayJSON(dateFromGregorian(year, month, d), year, month, d, name))
+		}
+	}
+	return entries
+}
+
+// printHolidaysJSON prints the holidays of a month as a JSON array.
+func printHolidaysJSON(entries []holidayJSON) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return checkStdout(enc.Encode(entries))
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// parseShamsyDate parses and validates a Shamsi date, including month length.
+func parseShamsyDate(dateStr string) (int, int, int, error) {
+	year, month, day, err := parseDate(dateStr)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if day > shamsy.MonthDays(year, month) {
+		return 0, 0, 0, withCode(codeInvalidDate, fmt.Errorf("invalid Shamsi date %s: %s %d has %d days", dateStr, shamsyMonths[month-1], year, shamsy.MonthDays(year, month)))
+	}
+	if !shamsy.InRange(year) {
+		return 0, 0, 0, withCode(codeInvalidDate, fmt.Errorf("%s is outside the supported range %s", dateStr, supportedRange(false)))
+	}
+	return year, month, day, nil
+}
+
+// holidaysBetween fetches every year touched by the inclusive Shamsi range
+// and returns the merged calendar of those years.
+func holidaysBetween(from, to shamsy.Date) (*shamsy.HolidayCalendar, error) {
+	if from.Compare(to) > 0 {
+		return nil, withCode(codeInvalidArgument, fmt.Errorf("start date %s is after end date %s", from, to))
+	}
+	var cals []*shamsy.HolidayCalendar
+	for y := from.Year; y <= to.Year; y++ {
+		cal, err := fetchHolidays(y)
+		if err != nil {
+			return nil, err
+		}
+		cals = append(cals, cal)
+	}
+	return shamsy.Merge(cals...), nil
+}
+
+// handleHolidaysBetween lists the holidays between two Shamsi dates with their
+// Gregorian equivalents and weekdays.
+func handleHolidaysBetween(fromStr, toStr string) error {
+	fy, fm, fd, err := parseShamsyDate(fromStr)
+	if err != nil {
+		return err
+	}
+	ty, tm, td, err := parseShamsyDate(toStr)
+	if err != nil {
+		return err
+	}
+	from, to := shamsy.Date{Year: fy, Month: fm, Day: fd}, shamsy.Date{Year: ty, Month: tm, Day: td}
+	cal, err := holidaysBetween(from, to)
+	if err != nil {
+		return err
+	}
+	entries := cal.Between(from, to)
+	fmt.Println(rgb(cyan, strings.Repeat("=", 60)))
+	fmt.Println(rgb(purple, withIcon("📌", fmt.Sprintf("Holidays from %s to %s", from, to))))
+	fmt.Println(rgb(cyan, strings.Repeat("-", 60)))
+	if len(entries) == 0 {
+		fmt.Println("No holidays in this range.")
+	}
+	for _, e := range entries {
+		g := e.Gregorian
+		if _, err := fmt.Printf("- %s  %s  %s: %s\n",
+			rgb(yellow, e.Date.String()),
+			rgb(blue, shamsy.FormatGregorian(g.Year, g.Month, g.Day, "YYYY/MM/DD")),
+			rgb(cyan, fmt.Sprintf("%-9s", g.DayWeek)),
+			rgb(offday, holidayText(e.Name))); err != nil {
+			return checkStdout(err)
+		}
+	}
+	days := to.EpochDays() - from.EpochDays() + 1
+	fmt.Println(rgb(cyan, strings.Repeat("-", 60)))
+	fmt.Printf("%s: %s\n", rgb(green, "Holidays"), rgb(offday, fmt.Sprint(len(entries))))
+	fmt.Printf("%s: %s\n", rgb(green, "Off days"), rgb(offday, fmt.Sprintf("%d of %d (holidays plus Fridays)", days-cal.WorkingDays(from, to), days)))
+	printFixedOnlyNote()
+	fmt.Println(rgb(cyan, strings.Repeat("=", 60)))
+	return nil
+}
+
+// handleHolidays implements "holidays --from DATE --to DATE".
+func handleHolidays(args []string) error {
+	fs := flag.NewFlagSet("holidays", flag.ContinueOnError)
+	fromStr := fs.String("from", "", "First Shamsi date of the range")
+	toStr := fs.String("to", "", "Last Shamsi date of the range")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 || *fromStr == "" || *toStr == "" {
+		return withCode(codeUsage, fmt.Errorf("usage: shamsy-calendar holidays --from YYYY/MM/DD --to YYYY/MM/DD"))
+	}
+	return handleHolidaysBetween(*fromStr, *toStr)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// jsonOutput switches commands that support it to JSON output.
+var jsonOutput bool
+
+// MonthInfo describes the layout of a Shamsi month. FirstWeekday counts from
+// Saturday (0) to Friday (6), matching the calendar's columns.
+type MonthInfo struct {
+	FirstWeekday   int    `json:"firstWeekday"`
+	Days           int    `json:"days"`
+	LeapYear       bool   `json:"leapYear"`
+	GregorianStart string `json:"gregorianStart"`
+	GregorianEnd   string `json:"gregorianEnd"`
+	// WorkingDays and HolidayClasses are only set when the holidays of the
+	// month are cached, or with --fixed-only; HolidayClasses lists what was
+	// counted as off.
+	WorkingDays    int      `json:"workingDays,omitempty"`
+	HolidayClasses []string `json:"holidayClasses,omitempty"`
+}
+
+// shamsyMonthInfo computes the MonthInfo of a Shamsi month.
+func shamsyMonthInfo(jy, jm int) MonthInfo {
+	days := shamsy.MonthDays(jy, jm)
+	sy, sm, sd := toGregorian(jy, jm, 1)
+	ey, em, ed := toGregorian(jy, jm, days)
+	return MonthInfo{
+		FirstWeekday:   getFirstWeekday(jy, jm),
+		Days:           days,
+		LeapYear:       shamsy.IsLeapYear(jy),
+		GregorianStart: shamsy.FormatGregorian(sy, sm, sd, "YYYY-MM-DD"),
+		GregorianEnd:   shamsy.FormatGregorian(ey, em, ed, "YYYY-MM-DD"),
+	}
+}
+
+// parseYearMonth validates year and month arguments.
+func parseYearMonth(yearStr, monthStr string) (int, int, error) {
+	y, err := strconv.Atoi(yearStr)
+	if err != nil || !shamsy.InRange(y) {
+		return 0, 0, withCode(codeInvalidArgument, fmt.Errorf("invalid year argument %q", yearStr))
+	}
+	m, err := strconv.Atoi(monthStr)
+	if err != nil || m < 1 || m > 12 {
+		return 0, 0, withCode(codeInvalidArgument, fmt.Errorf("invalid month argument %q: month must be between 1 and 12", monthStr))
+	}
+	return y, m, nil
+}
+
+// handleInfo implements "info month YEAR MONTH [--json]".
+func handleInfo(args []string) error {
+	fs := flag.NewFlagSet("info", flag.ContinueOnError)
+	fs.BoolVar(&jsonOutput, "json", jsonOutput, "Print JSON")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 3 || positional[0] != "month" {
+		return withCode(codeUsage, fmt.Errorf("usage: shamsy-calendar info month YEAR MONTH [--json]"))
+	}
+	jy, jm, err := parseYearMonth(positional[1], positional[2])
+	if err != nil {
+		return err
+	}
+	info := shamsyMonthInfo(jy, jm)
+	if holidays, err := cachedMonthHolidays(jy, jm); err == nil {
+		info.WorkingDays = holidays.WorkingDays(shamsy.Date{Year: jy, Month: jm, Day: 1},
+			shamsy.Date{Year: jy, Month: jm, Day: info.Days})
+		info.HolidayClasses = holidayClasses()
+	}
+	if jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return checkStdout(enc.Encode(info))
+	}
+	shamsyWeekdays := []string{"Saturday", "Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday"}
+	fmt.Println(rgb(cyan, strings.Repeat("=", 60)))
+	fmt.Println(rgb(purple, withIcon("🗓 ", fmt.Sprintf("%s %d", shamsyMonths[jm-1], jy))))
+	fmt.Println(rgb(cyan, strings.Repeat("-", 60)))
+	fmt.Printf("%s: %s\n", rgb(green, "First weekday"), rgb(cyan, shamsyWeekdays[info.FirstWeekday]))
+	fmt.Printf("%s: %s\n", rgb(green, "Days"), rgb(cyan, fmt.Sprint(info.Days)))
+	fmt.Printf("%s: %s\n", rgb(green, "Leap year"), rgb(cyan, fmt.Sprint(info.LeapYear)))
+	fmt.Printf("%s: %s\n", rgb(green, "Gregorian"), rgb(blue, info.GregorianStart+" – "+info.GregorianEnd))
+	if info.HolidayClasses != nil {
+		fmt.Printf("%s: %s\n", rgb(green, "Working days"), rgb(cyan, fmt.Sprint(info.WorkingDays)))
+		printFixedOnlyNote()
+	}
+	fmt.Println(rgb(cyan, strings.Repeat("=", 60)))
+	return nil
+}
+
+// cachedMonthHolidays is fetchMonthHolidays without the network: info is a
+// thin layout query, so the working days are only added when the holidays
+// are at hand.
+func cachedMonthHolidays(jy, jm int) (*shamsy.HolidayCalendar, error) {
+	opts := holidayOptions
+	opts.Observed = observedMode
+	opts.Offline = true
+	opts.Progress = nil
+	if fixedOnly {
+		opts = fixedOptions(opts)
+	}
+	cal, err := shamsy.LoadMonthHolidays(fetchContext, jy, jm, opts)
+	if err != nil {
+		return nil, err
+	}
+	return cal.WithHolidays(ruleHolidays(jy)), nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+func TestShamsyMonthInfo(t *testing.T) {
+	tests := []struct {
+		jy, jm int
+		want   MonthInfo
+	}{
+		// 1 Farvardin 1404 was a Friday.
+		{1404, 1, MonthInfo{FirstWeekday: 6, Days: 31, GregorianStart: "2025-03-21", GregorianEnd: "2025-04-20"}},
+		{1404, 7, MonthInfo{FirstWeekday: 3, Days: 30, GregorianStart: "2025-09-23", GregorianEnd: "2025-10-22"}},
+		{1403, 12, MonthInfo{FirstWeekday: 4, Days: 30, LeapYear: true, GregorianStart: "2025-02-19", GregorianEnd: "2025-03-20"}},
+		{1404, 12, MonthInfo{FirstWeekday: 6, Days: 29, GregorianStart: "2026-02-20", GregorianEnd: "2026-03-20"}},
+	}
+	for _, tt := range tests {
+		if got := shamsyMonthInfo(tt.jy, tt.jm); got.FirstWeekday != tt.want.FirstWeekday || got.Days != tt.want.Days ||
+			got.LeapYear != tt.want.LeapYear || got.GregorianStart != tt.want.GregorianStart || got.GregorianEnd != tt.want.GregorianEnd {
+			t.Errorf("shamsyMonthInfo(%d, %d) = %+v, want %+v", tt.jy, tt.jm, got, tt.want)
+		}
+	}
+}
+
+func TestMonthInfoJSON(t *testing.T) {
+	data, err := json.Marshal(shamsyMonthInfo(1404, 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"firstWeekday":3,"days":30,"leapYear":false,"gregorianStart":"2025-09-23","gregorianEnd":"2025-10-22"}`
+	if string(data) != want {
+		t.Errorf("JSON = %s, want %s", data, want)
+	}
+}
+
+func TestParseYearMonth(t *testing.T) {
+	tests := []struct {
+		year, month string
+		jy, jm      int
+		wantErr     bool
+	}{
+		{"1404", "7", 1404, 7, false},
+		{"1404", "07", 1404, 7, false},
+		{"1404", "0", 0, 0, true},
+		{"1404", "13", 0, 0, true},
+		{"1404", "mehr", 0, 0, true},
+		{"0", "1", 0, 0, true},
+		{"99999", "1", 0, 0, true},
+		{"abc", "1", 0, 0, true},
+	}
+	for _, tt := range tests {
+		jy, jm, err := parseYearMonth(tt.year, tt.month)
+		if (err != nil) != tt.wantErr || jy != tt.jy || jm != tt.jm {
+			t.Errorf("parseYearMonth(%q, %q) = %d, %d, %v", tt.year, tt.month, jy, jm, err)
+			continue
+		}
+		if err != nil && errorCode(err) != codeInvalidArgument {
+			t.Errorf("parseYearMonth(%q, %q): error code %s, want %s", tt.year, tt.month, errorCode(err), codeInvalidArgument)
+		}
+	}
+}
+
+func TestInfoReadsOnlyCachedHolidays(t *testing.T) {
+	var requests atomic.Int32
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.Error(w, "unexpected request", http.StatusInternalServerError)
+	}))
+	defer api.Close()
+	saved, savedJSON := holidayOptions, jsonOutput
+	t.Cleanup(func() { holidayOptions, jsonOutput = saved, savedJSON })
+	holidayOptions.CacheDir = t.TempDir()
+	holidayOptions.Providers = []shamsy.Provider{shamsy.APIProvider{URL: api.URL}}
+	jsonOutput = true
+	rec := recordStatus(t)
+
+	info := func() MonthInfo {
+		var err error
+		out := captureStdout(func() { err = handleInfo([]string{"month", "1404", "7"}) })
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got MonthInfo
+		if err := json.Unmarshal([]byte(out), &got); err != nil {
+			t.Fatalf("%v: %s", err, out)
+		}
+		return got
+	}
+	if got := info(); got.WorkingDays != 0 || got.HolidayClasses != nil {
+		t.Errorf("uncached: working days %d, classes %v, want none", got.WorkingDays, got.HolidayClasses)
+	}
+	if n := requests.Load(); n != 0 || len(rec.started) != 0 {
+		t.Errorf("info asked the API %d times and reported %q", n, rec.started)
+	}
+
+	// 1404/07/13 falls on a Sunday; Mehr has four Fridays.
+	path := filepath.Join(holidayOptions.CacheDir, "holidays_1404.json")
+	if err := os.WriteFile(path, []byte(`{"1404-07-13": "Holiday"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := info(); got.WorkingDays != 25 || got.HolidayClasses == nil {
+		t.Errorf("cached: working days %d, classes %v, want 25 and the classes", got.WorkingDays, got.HolidayClasses)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("info asked the API %d times", n)
+	}
+}
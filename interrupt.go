@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// fetchContext is the context every holiday fetch runs under. main replaces
+// it with one that is cancelled on SIGINT and after --timeout.
+var fetchContext = context.Background()
+
+// fetchTimeout limits how long all holiday fetches of a run may take
+// together; 0 means no limit.
+var fetchTimeout time.Duration
+
+// setupFetchContext makes SIGINT cancel fetches in flight and applies
+// --timeout. After the first SIGINT the default handling is restored, so a
+// second one terminates immediately. The returned function releases the
+// context's resources and the SIGINT handler.
+func setupFetchContext() context.CancelFunc {
+	interrupted, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	context.AfterFunc(interrupted, stop)
+	fetchContext = interrupted
+	if fetchTimeout <= 0 {
+		return stop
+	}
+	ctx, cancel := context.WithTimeout(interrupted, fetchTimeout)
+	fetchContext = ctx
+	return func() {
+		cancel()
+		stop()
+	}
+}
+
+// fetchError explains a fetch that failed because fetchContext ended.
+func fetchError(err error) error {
+	switch {
+	case errors.Is(fetchContext.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("fetching holidays timed out after %v", fetchTimeout)
+	case errors.Is(fetchContext.Err(), context.Canceled):
+		return fmt.Errorf("fetching holidays was interrupted")
+	}
+	return err
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// withFetchTimeout sets fetchTimeout and restores fetchContext after the test.
+func withFetchTimeout(t *testing.T, d time.Duration) {
+	savedTimeout, savedContext := fetchTimeout, fetchContext
+	fetchTimeout = d
+	t.Cleanup(func() { fetchTimeout, fetchContext = savedTimeout, savedContext })
+}
+
+func TestFetchContextTimeout(t *testing.T) {
+	withFetchTimeout(t, 20*time.Millisecond)
+	release := setupFetchContext()
+	defer release()
+	if _, ok := fetchContext.Deadline(); !ok {
+		t.Fatal("--timeout set no deadline")
+	}
+	<-fetchContext.Done()
+	if err := fetchError(errors.New("request failed")); err == nil || err.Error() != "fetching holidays timed out after 20ms" {
+		t.Errorf("fetchError = %v", err)
+	}
+}
+
+func TestFetchContextInterrupt(t *testing.T) {
+	for _, timeout := range []time.Duration{0, time.Hour} {
+		withFetchTimeout(t, timeout)
+		release := setupFetchContext()
+		if err := syscall.Kill(os.Getpid(), syscall.SIGINT); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case <-fetchContext.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout %v: SIGINT did not cancel the fetch context", timeout)
+		}
+		if err := fetchError(errors.New("request failed")); err == nil || err.Error() != "fetching holidays was interrupted" {
+			t.Errorf("timeout %v: fetchError = %v", timeout, err)
+		}
+		release()
+	}
+}
+
+func TestFetchContextRelease(t *testing.T) {
+	withFetchTimeout(t, time.Hour)
+	release := setupFetchContext()
+	ctx := fetchContext
+	release()
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		t.Errorf("after release the context is %v, want canceled", ctx.Err())
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import "fmt"
+
+// weekdayLang and monthLang select the language of the weekday header row
+// and of the month titles, "en" (transliterated) or "fa" (Persian script).
+// Day numbers and all other output stay as they are.
+var weekdayLang, monthLang = "en", "en"
+
+var faWeekDays = []string{"ش", "ی", "د", "س", "چ", "پ", "ج"}
+var faGregorianWeekDays = []string{"ی", "د", "س", "چ", "پ", "ج", "ش"}
+
+var faShamsyMonths = []string{
+	"فروردین", "اردیبهشت", "خرداد", "تیر", "مرداد", "شهریور",
+	"مهر", "آبان", "آذر", "دی", "بهمن", "اسفند",
+}
+
+var faGregorianMonths = []string{
+	"ژانویه", "فوریه", "مارس", "آوریل", "مه", "ژوئن",
+	"ژوئیه", "اوت", "سپتامبر", "اکتبر", "نوامبر", "دسامبر",
+}
+
+// parseLang validates the value of a --*-lang flag.
+func parseLang(name, value string) (string, error) {
+	if value != "en" && value != "fa" {
+		return "", withCode(codeInvalidArgument, fmt.Errorf("invalid --%s %q: must be en or fa", name, value))
+	}
+	return value, nil
+}
+
+// shamsyWeekHeader returns the Shamsi weekday labels, Saturday first.
+func shamsyWeekHeader() []string {
+	if weekdayLang == "fa" {
+		return faWeekDays
+	}
+	return weekDays
+}
+
+// gregorianWeekLabels returns the Gregorian weekday labels, Sunday first.
+func gregorianWeekLabels() []string {
+	if weekdayLang == "fa" {
+		return faGregorianWeekDays
+	}
+	return gregorianWeekDays
+}
+
+// shamsyMonthTitle returns the title of a Shamsi month, e.g. "Mehr 1404".
+func shamsyMonthTitle(jy, jm int) string {
+	if monthLang == "fa" {
+		return fmt.Sprintf("%s %d", faShamsyMonths[jm-1], jy)
+	}
+	return fmt.Sprintf("%s %d", shamsyMonths[jm-1], jy)
+}
+
+// gregorianMonthTitle returns the title of a Gregorian month.
+func gregorianMonthTitle(year, month int) string {
+	if monthLang == "fa" {
+		return fmt.Sprintf("%s %d", faGregorianMonths[month-1], year)
+	}
+	return fmt.Sprintf("%s %d", gregorianMonths[month-1], year)
+}
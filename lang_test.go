@@ -0,0 +1,47 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLang(t *testing.T) {
+	for value, ok := range map[string]bool{"en": true, "fa": true, "": false, "FA": false, "de": false} {
+		got, err := parseLang("weekday-lang", value)
+		if (err == nil) != ok || (ok && got != value) {
+			t.Errorf("parseLang(%q) = %q, %v", value, got, err)
+		}
+	}
+}
+
+func TestLangCombinations(t *testing.T) {
+	const days = "               1   2   3   4"
+	tests := []struct {
+		weekday, month string
+		title, header  string
+	}{
+		{"en", "en", "==========Mehr 1404===========", "  Sh  Ye  Do  Se  Ch  Pa  Jo"},
+		{"en", "fa", "===========مهر 1404===========", "  Sh  Ye  Do  Se  Ch  Pa  Jo"},
+		{"fa", "en", "==========Mehr 1404===========", "   ش   ی   د   س   چ   پ   ج"},
+		{"fa", "fa", "===========مهر 1404===========", "   ش   ی   د   س   چ   پ   ج"},
+	}
+	savedWeekday, savedMonth := weekdayLang, monthLang
+	defer func() { weekdayLang, monthLang = savedWeekday, savedMonth }()
+	opts := monthOptions{}
+	for _, tt := range tests {
+		weekdayLang, monthLang = tt.weekday, tt.month
+		out := renderText(func() { printshamsyCalendar(1404, 7, noHighlight, nil, opts) })
+		lines := strings.Split(strings.TrimSuffix(out, "\n\n"), "\n")
+		name := "--weekday-lang " + tt.weekday + " --month-lang " + tt.month
+		if len(lines) < 3 || lines[0] != tt.title || lines[1] != tt.header || lines[2] != days {
+			t.Errorf("%s: got\n%s", name, out)
+			continue
+		}
+		// The grid keeps its alignment in every combination.
+		for i, line := range lines {
+			if w := visibleWidth(line); w > opts.monthWidth() || (i > 0 && w != visibleWidth(days)) {
+				t.Errorf("%s: line %d %q is %d columns wide", name, i, line, w)
+			}
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// LeapYear is one leap year listed by the leaps command. Gap is the number
+// of years since the previous leap year, 0 when there is none. For Shamsi
+// years Date is 30 Esfand in Gregorian (YYYY-MM-DD) and GregorianYear its
+// year; for Gregorian years Date is 29 February in Shamsi (YYYY/MM/DD).
+type LeapYear struct {
+	Year          int    `json:"year"`
+	Gap           int    `json:"gap,omitempty"`
+	GregorianYear int    `json:"gregorianYear,omitempty"`
+	Date          string `json:"date"`
+}
+
+// parseYearRange parses "FROM-TO" or a single year.
+func parseYearRange(s string, isGregorian bool) (int, int, error) {
+	fromStr, toStr, found := strings.Cut(s, "-")
+	if !found {
+		toStr = fromStr
+	}
+	from, err1 := strconv.Atoi(strings.TrimSpace(fromStr))
+	to, err2 := strconv.Atoi(strings.TrimSpace(toStr))
+	if err1 != nil || err2 != nil || !yearInRange(from, isGregorian) || !yearInRange(to, isGregorian) || from > to {
+		return 0, 0, withCode(codeInvalidArgument, fmt.Errorf("invalid year range %q (supported: %s)", s, supportedRange(isGregorian)))
+	}
+	return from, to, nil
+}
+
+// leapYears lists the leap years from..to, with the gap to the previous leap
+// year even when that one lies before from.
+func leapYears(from, to int, isGregorian bool) []LeapYear {
+	isLeap, minYear := shamsy.IsLeapYear, shamsy.MinYear
+	if isGregorian {
+		isLeap, minYear = isGregorianLeapYear, 1
+	}
+	prev := 0
+	for y := from - 1; y >= minYear && y >= from-8; y-- {
+		if isLeap(y) {
+			prev = y
+			break
+		}
+	}
+	leaps := []LeapYear{}
+	for y := from; y <= to; y++ {
+		if !isLeap(y) {
+			continue
+		}
+		leap := LeapYear{Year: y}
+		if prev > 0 {
+			leap.Gap = y - prev
+		}
+		if isGregorian {
+			leap.Date = dateFromGregorian(y, 2, 29).String()
+		} else {
+			gy, gm, gd := toGregorian(y, 12, 30)
+			leap.GregorianYear = gy
+			leap.Date = shamsy.FormatGregorian(gy, gm, gd, "YYYY-MM-DD")
+		}
+		leaps = append(leaps, leap)
+		prev = y
+	}
+	return leaps
+}
+
+// handleLeaps implements "leaps FROM-TO [--gregorian] [--json]".
+func handleLeaps(args []string, isGregorian bool) error {
+	fs := flag.NewFlagSet("leaps", flag.ContinueOnError)
+	fs.BoolVar(&isGregorian, "gregorian", isGregorian, "List Gregorian leap years")
+	fs.BoolVar(&jsonOutput, "json", jsonOutput, "Print JSON")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return withCode(codeUsage, fmt.Errorf("usage: shamsy-calendar leaps FROM-TO [--gregorian] [--json]"))
+	}
+	from, to, err := parseYearRange(positional[0], isGregorian)
+	if err != nil {
+		return err
+	}
+	leaps := leapYears(from, to, isGregorian)
+	if jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return checkStdout(enc.Encode(leaps))
+	}
+	calendar := "Shamsi"
+	if isGregorian {
+		calendar = "Gregorian"
+	}
+	fmt.Println(rgb(cyan, strings.Repeat("=", 60)))
+	fmt.Println(rgb(purple, withIcon("🗓 ", fmt.Sprintf("%s leap years %d–%d", calendar, from, to))))
+	fmt.Println(rgb(cyan, strings.Repeat("-", 60)))
+	if len(leaps) == 0 {
+		fmt.Println("No leap years in this range.")
+	}
+	for _, l := range leaps {
+		gap := "      "
+		if l.Gap > 0 {
+			gap = fmt.Sprintf("gap %-2d", l.Gap)
+		}
+		var err error
+		if isGregorian {
+			_, err = fmt.Printf("%s  %s  %s\n", rgb(blue, fmt.Sprintf("%4d", l.Year)), rgb(cyan, gap),
+				rgb(green, "29 February = ")+rgb(yellow, l.Date))
+		} else {
+			_, err = fmt.Printf("%s  %s  %s\n", rgb(yellow, fmt.Sprintf("%4d", l.Year)), rgb(cyan, gap),
+				rgb(green, "30 Esfand = ")+rgb(blue, l.Date))
+		}
+		if err != nil {
+			return checkStdout(err)
+		}
+	}
+	fmt.Println(rgb(cyan, strings.Repeat("=", 60)))
+	return nil
+}
@@ -1,137 +1,109 @@
 package main
 
 import (
-	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
-	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/schollz/progressbar/v3"
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
 )
 
 type Color struct{ r, g, b int }
 
-func rgb(c Color, s string) string {
-	return fmt.Sprintf("\x1b[38;2;%d;%d;%dm%s\x1b[0m", c.r, c.g, c.b, s)
-}
+// noColor makes rgb return its text unchanged.
+var noColor bool
 
-type CalendarResponse struct {
-	Status bool                 `json:"status"`
-	Result map[string]MonthData `json:"result"`
+func rgb(c Color, s string) string {
+	if noColor {
+		return s
+	}
+	return fmt.Sprintf("\x1b[%sm%s\x1b[0m", colorCode(c), s)
 }
 
-type MonthData map[string]DayData
+// observedMode moves holidays that fall on a Friday to the next working day.
+var observedMode bool
 
-type DayData struct {
-	Solar   DateInfo `json:"solar"`
-	Holiday bool     `json:"holiday"`
-	Event   []string `json:"event"`
+// holidayOptions configures how the holidays of a year are loaded.
+var holidayOptions = shamsy.Options{
+	OnCacheWriteError: func(err error) {
+		status.Warn(fmt.Sprintf("failed to save to cache: %v", err))
+	},
+	Progress: showFetchProgress,
 }
 
-type DateInfo struct {
-	Day     int    `json:"day"`
-	Month   int    `json:"month"`
-	Year    int    `json:"year"`
-	DayWeek string `json:"dayWeek"`
+// cacheDir returns the directory scal keeps its caches in.
+func cacheDir() (string, error) {
+	if holidayOptions.CacheDir != "" {
+		return holidayOptions.CacheDir, nil
+	}
+	return shamsy.DefaultCacheDir()
 }
 
-func fetchHolidays(year int) (map[string]string, error) {
-	cacheDir, err := os.UserCacheDir()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get cache directory: %v", err)
-	}
-	cacheFile := filepath.Join(cacheDir, "shamsy_calendar", fmt.Sprintf("holidays_%d.json", year))
-	if cachedHolidays, err := readFromCache(cacheFile); err == nil {
-		return cachedHolidays, nil
-	}
-	bar := progressbar.NewOptions(-1,
-		progressbar.OptionSetDescription("Fetching holidays..."),
-		progressbar.OptionSpinnerType(14),
-		progressbar.OptionSetWidth(20),
-	)
-	defer bar.Close()
-	url := fmt.Sprintf("https://pnldev.com/api/calender?year=%d&holiday=true", year)
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch holidays: %v", err)
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %v", err)
-	}
-	var calendar CalendarResponse
-	if err := json.Unmarshal(body, &calendar); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON: %v", err)
-	}
-	if !calendar.Status {
-		return nil, fmt.Errorf("API returned status false")
-	}
-	holidays := make(map[string]string)
-	for _, days := range calendar.Result {
-		for _, dayData := range days {
-			if dayData.Holiday {
-				key := fmt.Sprintf("%d-%02d-%02d", dayData.Solar.Year, dayData.Solar.Month, dayData.Solar.Day)
-				if len(dayData.Event) > 0 {
-					holidays[key] = strings.Join(dayData.Event, "; ")
-				} else {
-					holidays[key] = "Holiday"
-				}
-			}
-		}
-	}
-	if err := saveToCache(cacheFile, holidays); err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: failed to save to cache: %v\n", err)
-	}
-	return holidays, nil
+// fetchHolidays loads the holiday calendar of a Shamsi year, shifted to the
+// observed days with --observed and extended by the configured rules.
+func fetchHolidays(year int) (*shamsy.HolidayCalendar, error) {
+	return fetchMonthHolidays(year, 0)
 }
 
-func readFromCache(cacheFile string) (map[string]string, error) {
-	data, err := os.ReadFile(cacheFile)
-	if err != nil {
-		return nil, err
+// fetchMonthHolidays is fetchHolidays for views of a single Shamsi month:
+// when the year is not cached, only the month is downloaded. A month of 0
+// loads the whole year.
+func fetchMonthHolidays(year, month int) (*shamsy.HolidayCalendar, error) {
+	opts := holidayOptions
+	opts.Observed = observedMode
+	if fixedOnly {
+		opts = fixedOptions(opts)
 	}
-	var holidays map[string]string
-	if err := json.Unmarshal(data, &holidays); err != nil {
-		return nil, err
+	var cal *shamsy.HolidayCalendar
+	var err error
+	if month > 0 {
+		cal, err = shamsy.LoadMonthHolidays(fetchContext, year, month, opts)
+	} else {
+		cal, err = shamsy.LoadHolidays(fetchContext, year, opts)
 	}
-	return holidays, nil
-}
-
-func saveToCache(cacheFile string, holidays map[string]string) error {
-	if err := os.MkdirAll(filepath.Dir(cacheFile), 0755); err != nil {
-		return fmt.Errorf("failed to create cache directory: %v", err)
+	if err != nil && forecastMode && !fixedOnly {
+		status.Warn(fmt.Sprintf("no holiday data for %d (%v); showing a forecast", year, fetchError(err)))
+		return forecastCalendar(year)
 	}
-	data, err := json.Marshal(holidays)
 	if err != nil {
-		return fmt.Errorf("failed to marshal holidays to JSON: %v", err)
-	}
-	if err := os.WriteFile(cacheFile, data, 0644); err != nil {
-		return fmt.Errorf("failed to write cache file: %v", err)
+		return nil, withCode(codeHolidays, fetchError(err))
 	}
-	return nil
+	return cal.WithHolidays(ruleHolidays(year)), nil
 }
 
+// offday colors official holidays and weekendColor the weekend days that
+// are not holidays, dimmer so that the two can be told apart.
 var (
-	offday = Color{255, 0, 0}
-	red    = Color{255, 255, 255}
-	green  = Color{188, 188, 188}
-	blue   = Color{135, 206, 235}
-	yellow = Color{255, 255, 0}
-	cyan   = Color{0, 255, 255}
-	purple = Color{200, 100, 255}
+	offday       = Color{255, 0, 0}
+	weekendColor = Color{175, 60, 60}
+	red          = Color{255, 255, 255}
+	green        = Color{188, 188, 188}
+	blue         = Color{135, 206, 235}
+	yellow       = Color{255, 255, 0}
+	cyan         = Color{0, 255, 255}
+	purple       = Color{200, 100, 255}
 )
 
+// weekdayTints holds one subtle color per weekday column, indexed like the
+// calendar's header row. They are used instead of blue with --rainbow-weekdays.
+var weekdayTints = []Color{
+	{135, 206, 235},
+	{150, 220, 180},
+	{210, 200, 140},
+	{230, 170, 140},
+	{200, 160, 220},
+	{160, 180, 240},
+	{140, 220, 220},
+}
+
+// rainbowWeekdays enables the per-weekday tints for regular days.
+var rainbowWeekdays bool
+
 var shamsyMonths = []string{
 	"Farvardin", "Ordibehesht", "Khordad", "Tir", "Mordad", "Shahrivar",
 	"Mehr", "Aban", "Azar", "Dey", "Bahman", "Esfand",
@@ -146,36 +118,10 @@ var weekDays = []string{"Sh", "Ye", "Do", "Se", "Ch", "Pa", "Jo"}
 var gregorianWeekDays = []string{"Su", "Mo", "Tu", "We", "Th", "Fr", "Sa"}
 var goToshamsyWeekday = []int{1, 2, 3, 4, 5, 6, 0}
 
-func isshamsyLeapYear(year int) bool {
-	leapYears := []int{1, 5, 9, 13, 17, 22, 26, 30}
-	cycle := (year - 474) % 2820
-	mod := cycle % 33
-	for _, v := range leapYears {
-		if mod == v {
-			return true
-		}
-	}
-	return false
-}
-
 func isGregorianLeapYear(year int) bool {
 	return (year%4 == 0 && year%100 != 0) || (year%400 == 0)
 }
 
-func shamsyMonthDays(year, month int) int {
-	if month <= 6 {
-		return 31
-	} else if month <= 11 {
-		return 30
-	} else if month == 12 {
-		if isshamsyLeapYear(year) {
-			return 30
-		}
-		return 29
-	}
-	return 0
-}
-
 func gregorianMonthDays(year, month int) int {
 	daysInMonth := []int{31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
 	if month == 2 && isGregorianLeapYear(year) {
@@ -184,136 +130,20 @@ func gregorianMonthDays(year, month int) int {
 	return daysInMonth[month-1]
 }
 
-func gregorianToshamsy(gy, gm, gd int) (int, int, int) {
-	var jy, jm, jd int
-
-	if gy > 1600 {
-		jy = 979
-		gy -= 1600
-	} else {
-		jy = 0
-		gy -= 621
-	}
-
-	if gm > 2 {
-		gy2 := gy
-		totalDays := 365*gy + ((gy2 + 3) / 4) - ((gy2 + 99) / 100) + ((gy2 + 399) / 400) - 80 + gd
-		monthDays := []int{0, 31, 59, 90, 120, 151, 181, 212, 243, 273, 304, 334}
-		totalDays += monthDays[gm-1]
-
-		jy += 33 * (totalDays / 12053)
-		totalDays %= 12053
-
-		jy += 4 * (totalDays / 1461)
-		totalDays %= 1461
-
-		if totalDays > 365 {
-			jy += (totalDays - 1) / 365
-			totalDays = (totalDays - 1) % 365
-		}
-
-		if totalDays < 186 {
-			jm = 1 + totalDays/31
-			jd = 1 + (totalDays % 31)
-		} else {
-			jm = 7 + (totalDays-186)/30
-			jd = 1 + ((totalDays - 186) % 30)
-		}
-
-		return jy, jm, jd
-	} else {
-		gy2 := gy - 1
-		totalDays := 365*gy + ((gy2 + 3) / 4) - ((gy2 + 99) / 100) + ((gy2 + 399) / 400) - 80 + gd
-		monthDays := []int{0, 31, 59}
-		totalDays += monthDays[gm-1]
-
-		jy += 33 * (totalDays / 12053)
-		totalDays %= 12053
-
-		jy += 4 * (totalDays / 1461)
-		totalDays %= 1461
-
-		if totalDays > 365 {
-			jy += (totalDays - 1) / 365
-			totalDays = (totalDays - 1) % 365
-		}
-
-		if totalDays < 186 {
-			jm = 1 + totalDays/31
-			jd = 1 + (totalDays % 31)
-		} else {
-			jm = 7 + (totalDays-186)/30
-			jd = 1 + ((totalDays - 186) % 30)
-		}
-
-		return jy, jm, jd
-	}
-}
-
-func shamsyToGregorian(jy, jm, jd int) (int, int, int) {
-	var sal_a, gy, gm, gd, days int
-
-	jy += 1595
-	days = -355668 + (365 * jy) + ((jy / 33) * 8) + (((jy % 33) + 3) / 4) + jd
-
-	if jm < 7 {
-		days += (jm - 1) * 31
-	} else {
-		days += ((jm - 7) * 30) + 186
-	}
-
-	gy = 400 * (days / 146097)
-	days %= 146097
-
-	if days > 36524 {
-		days--
-		gy += 100 * (days / 36524)
-		days %= 36524
-		if days >= 365 {
-			days++
-		}
-	}
-
-	gy += 4 * (days / 1461)
-	days %= 1461
-
-	if days > 365 {
-		gy += (days - 1) / 365
-		days = (days - 1) % 365
-	}
-
-	gd = days + 1
-
-	sal_a = 0
-	if (gy%4 == 0 && gy%100 != 0) || gy%400 == 0 {
-		sal_a = 1
-	}
-
-	monthDays := []int{31, 28 + sal_a, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
-	gm = 0
-	for gm < 12 && gd > monthDays[gm] {
-		gd -= monthDays[gm]
-		gm++
-	}
-	gm++
-
-	return gy, gm, gd
-}
-
 func getFirstWeekday(jy, jm int) int {
-	gy, gm, gd := shamsyToGregorian(jy, jm, 1)
-	t := time.Date(gy, time.Month(gm), gd, 0, 0, 0, 0, time.UTC)
-	return goToshamsyWeekday[int(t.Weekday())]
+	gy, gm, gd := toGregorian(jy, jm, 1)
+	return goToshamsyWeekday[int(shamsy.GregorianWeekday(gy, gm, gd))]
 }
 
 func getGregorianFirstWeekday(year, month int) int {
-	t := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
-	return int(t.Weekday())
+	return gregorianColumn(shamsy.GregorianWeekday(year, month, 1))
 }
 
+// ansiCode matches the color escape sequences rgb produces.
+var ansiCode = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]`)
+
 func stripAnsiCodes(s string) string {
-	re := regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]`)
-	return re.ReplaceAllString(s, "")
+	return ansiCode.ReplaceAllString(s, "")
 }
 
 var maxTitleWidth int
@@ -342,40 +172,135 @@ func init() {
 	}
 }
 
-func printshamsyCalendar(jy, jm, highlight int, holidays map[string]string) {
-	titleText := fmt.Sprintf("%s %d", shamsyMonths[jm-1], jy)
-	totalPad := maxTitleWidth - len(titleText)
+// monthOptions controls the decorations printed around a month grid.
+type monthOptions struct {
+	NoHeader          bool // omit the "==== Month Year ====" title line
+	NoWeekdayHeader   bool // omit the weekday names, keeping the day positions
+	NoTrailingNewline bool // omit the blank line after the grid
+	Mini              bool // use 3-column cells to fit narrow terminals
+	WeekNumbers       bool // prefix each week with its ISO week number (Gregorian grid)
+	PadAdjacent       bool // fill blank cells with the neighbouring months' days
+	ShowLength        bool // print the number of days right-aligned under the title
+}
+
+// cellWidth returns the width of one day cell.
+// A holiday marker widens the cells when a day number, the marker and a
+// separating space would not fit.
+func (o monthOptions) cellWidth() int {
+	cw := 4
+	if o.Mini {
+		cw = 3
+	}
+	if markerWidth > 0 && cw < 3+markerWidth {
+		cw = 3 + markerWidth
+	}
+	return cw
+}
+
+// monthWidth returns the visible width of a month rendered with o.
+func (o monthOptions) monthWidth() int {
+	width := maxTitleWidth
+	if o.Mini || 7*o.cellWidth() > width {
+		width = 7 * o.cellWidth()
+	}
+	if o.WeekNumbers {
+		width += o.cellWidth()
+	}
+	return width
+}
+
+// printMonthLength prints "(N days)" right-aligned to the month width, on a
+// line of its own so that the title stays centered and every month of the
+// year view keeps the same height.
+func printMonthLength(days int, opts monthOptions) {
+	if !opts.ShowLength {
+		return
+	}
+	text := fmt.Sprintf("(%d days)", days)
+	fmt.Println(rgb(green, strings.Repeat(" ", opts.monthWidth()-len(text))+text))
+}
+
+// monthTitle centers titleText in a line of "=" as wide as a rendered month.
+func monthTitle(titleText string, opts monthOptions) string {
+	totalPad := opts.monthWidth() - visibleWidth(titleText)
 	leftPad := totalPad / 2
 	rightPad := totalPad - leftPad
-	head := fmt.Sprintf("%s%s%s", strings.Repeat("=", leftPad), titleText, strings.Repeat("=", rightPad))
-	fmt.Println(rgb(red, head))
-	for _, wd := range weekDays {
-		cell := fmt.Sprintf("%4s", wd)
-		fmt.Print(rgb(green, cell))
+	return fmt.Sprintf("%s%s%s", strings.Repeat("=", leftPad), titleText, strings.Repeat("=", rightPad))
+}
+
+// shamsyDayColor picks the color of day d in a Shamsi month: today, marked
+// days, holidays and Fridays stand out from regular days, holidays in a
+// brighter red than Fridays.
+func shamsyDayColor(jy, jm, d, highlight int, holidays *shamsy.HolidayCalendar) Color {
+	gy, gm, gd := toGregorian(jy, jm, d)
+	weekday := shamsy.GregorianWeekday(gy, gm, gd)
+	if d == highlight {
+		return yellow
+	} else if isMarked(shamsy.Date{Year: jy, Month: jm, Day: d}) {
+		return markColor
+	} else if estimated[shamsy.Date{Year: jy, Month: jm, Day: d}] {
+		return estimatedColor
+	} else if _, ok := holidays.IsHoliday(shamsy.Date{Year: jy, Month: jm, Day: d}); ok {
+		return offday
+	} else if weekday == time.Friday {
+		return weekendColor
+	} else if halfDays[weekday] {
+		return halfDayColor
+	} else if rainbowWeekdays {
+		return weekdayTints[goToshamsyWeekday[int(weekday)]]
+	}
+	return blue
+}
+
+// gregorianDayColor picks the color of day d in a Gregorian month, using the
+// Shamsi holidays and the Saturday/Sunday weekend.
+func gregorianDayColor(year, month, d, highlight int, shamsyHolidays *shamsy.HolidayCalendar) Color {
+	weekday := shamsy.GregorianWeekday(year, month, d)
+	if d == highlight {
+		return yellow
+	} else if isMarked(dateFromGregorian(year, month, d)) {
+		return markColor
+	} else if estimated[dateFromGregorian(year, month, d)] {
+		return estimatedColor
+	} else if _, ok := shamsyHolidays.IsGregorianHoliday(year, month, d); ok {
+		return offday
+	} else if _, ok := lookupObservance(month, d); ok && gregorianEvents {
+		return observanceColor
+	} else if weekday == time.Saturday || weekday == time.Sunday {
+		return weekendColor
+	} else if halfDays[weekday] {
+		return halfDayColor
+	} else if rainbowWeekdays {
+		return weekdayTints[gregorianColumn(weekday)]
+	}
+	return blue
+}
+
+// printshamsyCalendar prints a Shamsi month with day highlight marked as
+// today; pass noHighlight for none.
+func printshamsyCalendar(jy, jm, highlight int, holidays *shamsy.HolidayCalendar, opts monthOptions) {
+	if !opts.NoHeader {
+		fmt.Println(rgb(red, monthTitle(shamsyMonthTitle(jy, jm), opts)))
+	}
+	printMonthLength(shamsy.MonthDays(jy, jm), opts)
+	cw := opts.cellWidth()
+	if !opts.NoWeekdayHeader {
+		for _, wd := range shamsyWeekHeader() {
+			cell := labelCell(wd, cw)
+			fmt.Print(rgb(green, cell))
+		}
+		fmt.Println()
 	}
-	fmt.Println()
 	first := getFirstWeekday(jy, jm)
 	currentPos := first
-	fmt.Print(strings.Repeat("    ", first))
-	days := shamsyMonthDays(jy, jm)
+	prevDays := previousShamsyMonthDays(jy, jm)
+	fmt.Print(adjacentCells(prevDays-first+1, first, cw, opts))
+	days := shamsy.MonthDays(jy, jm)
+	checkHighlight(highlight, days)
 	for d := 1; d <= days; d++ {
-		key := fmt.Sprintf("%d-%02d-%02d", jy, jm, d)
-		gy, gm, gd := shamsyToGregorian(jy, jm, d)
-		weekday := time.Date(gy, time.Month(gm), gd, 0, 0, 0, 0, time.Local).Weekday()
-		if d == highlight {
-			cell := fmt.Sprintf("%2d", d)
-			cell = fmt.Sprintf("%4s", cell)
-			fmt.Print(rgb(yellow, cell))
-		} else if _, ok := holidays[key]; ok {
-			cell := fmt.Sprintf("%4s", fmt.Sprintf("%2d", d))
-			fmt.Print(rgb(offday, cell))
-		} else if weekday == time.Friday {
-			cell := fmt.Sprintf("%4s", fmt.Sprintf("%2d", d))
-			fmt.Print(rgb(offday, cell))
-		} else {
-			cell := fmt.Sprintf("%4s", fmt.Sprintf("%2d", d))
-			fmt.Print(rgb(blue, cell))
-		}
+		_, holiday := holidays.IsHoliday(shamsy.Date{Year: jy, Month: jm, Day: d})
+		cell := mismatchCell(dayCell(d, cw, holiday), shamsyMismatch(jy, jm, d))
+		fmt.Print(rgb(shamsyDayColor(jy, jm, d, highlight, holidays), cell))
 		currentPos++
 		if currentPos%7 == 0 {
 			fmt.Println()
@@ -383,48 +308,48 @@ func printshamsyCalendar(jy, jm, highlight int, holidays map[string]string) {
 		}
 	}
 	if currentPos != 0 {
-		for i := currentPos; i < 7; i++ {
-			fmt.Print("    ")
-		}
+		fmt.Print(adjacentCells(1, 7-currentPos, cw, opts))
 		fmt.Println()
 	}
-	fmt.Print("\n")
+	if !opts.NoTrailingNewline {
+		fmt.Print("\n")
+	}
 }
 
-func printGregorianCalendar(year, month, highlight int, shamsyHolidays map[string]string) {
-	titleText := fmt.Sprintf("%s %d", gregorianMonths[month-1], year)
-	totalPad := maxTitleWidth - len(titleText)
-	leftPad := totalPad / 2
-	rightPad := totalPad - leftPad
-	head := fmt.Sprintf("%s%s%s", strings.Repeat("=", leftPad), titleText, strings.Repeat("=", rightPad))
-	fmt.Println(rgb(red, head))
-	for _, wd := range gregorianWeekDays {
-		cell := fmt.Sprintf("%4s", wd)
-		fmt.Print(rgb(green, cell))
+// printGregorianCalendar prints a Gregorian month with day highlight marked
+// as today; pass noHighlight for none.
+func printGregorianCalendar(year, month, highlight int, shamsyHolidays *shamsy.HolidayCalendar, opts monthOptions) {
+	if !opts.NoHeader {
+		fmt.Println(rgb(red, monthTitle(gregorianMonthTitle(year, month), opts)))
+	}
+	printMonthLength(gregorianMonthDays(year, month), opts)
+	cw := opts.cellWidth()
+	if !opts.NoWeekdayHeader {
+		if opts.WeekNumbers {
+			fmt.Print(rgb(purple, labelCell("Wk", cw)))
+		}
+		for _, wd := range gregorianWeekHeader() {
+			cell := labelCell(wd, cw)
+			fmt.Print(rgb(green, cell))
+		}
+		fmt.Println()
 	}
-	fmt.Println()
 	first := getGregorianFirstWeekday(year, month)
 	currentPos := first
-	fmt.Print(strings.Repeat("    ", first))
+	if opts.WeekNumbers {
+		fmt.Print(rgb(purple, dayCell(isoWeekOfRow(year, month, 1-first), cw, false)))
+	}
+	prevDays := previousGregorianMonthDays(year, month)
+	fmt.Print(adjacentCells(prevDays-first+1, first, cw, opts))
 	days := gregorianMonthDays(year, month)
+	checkHighlight(highlight, days)
 	for d := 1; d <= days; d++ {
-		jy, jm, jd := gregorianToshamsy(year, month, d)
-		key := fmt.Sprintf("%d-%02d-%02d", jy, jm, jd)
-		weekday := time.Date(year, time.Month(month), d, 0, 0, 0, 0, time.Local).Weekday()
-		if d == highlight {
-			cell := fmt.Sprintf("%2d", d)
-			cell = fmt.Sprintf("%4s", cell)
-			fmt.Print(rgb(yellow, cell))
-		} else if _, ok := shamsyHolidays[key]; ok {
-			cell := fmt.Sprintf("%4s", fmt.Sprintf("%2d", d))
-			fmt.Print(rgb(offday, cell))
-		} else if weekday == time.Saturday || weekday == time.Sunday {
-			cell := fmt.Sprintf("%4s", fmt.Sprintf("%2d", d))
-			fmt.Print(rgb(offday, cell))
-		} else {
-			cell := fmt.Sprintf("%4s", fmt.Sprintf("%2d", d))
-			fmt.Print(rgb(blue, cell))
+		if currentPos == 0 && d > 1 && opts.WeekNumbers {
+			fmt.Print(rgb(purple, dayCell(isoWeekOfRow(year, month, d), cw, false)))
 		}
+		_, holiday := shamsyHolidays.IsGregorianHoliday(year, month, d)
+		cell := mismatchCell(dayCell(d, cw, holiday), gregorianMismatch(year, month, d))
+		fmt.Print(rgb(gregorianDayColor(year, month, d, highlight, shamsyHolidays), cell))
 		currentPos++
 		if currentPos%7 == 0 {
 			fmt.Println()
@@ -432,114 +357,243 @@ func printGregorianCalendar(year, month, highlight int, shamsyHolidays map[strin
 		}
 	}
 	if currentPos != 0 {
-		for i := currentPos; i < 7; i++ {
-			fmt.Print("    ")
-		}
+		fmt.Print(adjacentCells(1, 7-currentPos, cw, opts))
 		fmt.Println()
 	}
-	fmt.Print("\n")
+	if !opts.NoTrailingNewline {
+		fmt.Print("\n")
+	}
 }
 
-func printHolidaysOfMonth(jy, jm int, holidays map[string]string) {
-	fmt.Println("📌 Holidays in this month:")
-	found := false
-	for d := 1; d <= shamsyMonthDays(jy, jm); d++ {
-		key := fmt.Sprintf("%d-%02d-%02d", jy, jm, d)
-		if desc, ok := holidays[key]; ok {
-			fmt.Printf("- %02d %s: %s\n", d, shamsyMonths[jm-1], desc)
-			found = true
+// captureStdout runs fn with os.Stdout redirected to a pipe and returns what
+// it printed. The pipe is drained while fn runs, so output larger than the
+// pipe buffer neither blocks fn nor gets cut off.
+func captureStdout(fn func()) string {
+	origStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	out := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		r.Close()
+		out <- data
+	}()
+	fn()
+	w.Close()
+	os.Stdout = origStdout
+	return string(<-out)
+}
+
+// yearWidth returns the width of a year view with cols month columns.
+func yearWidth(cols int, opts monthOptions) int {
+	return cols*opts.monthWidth() + (cols-1)*visibleWidth(yearGap)
+}
+
+// printYear lays out the twelve months rendered by renderMonth in a grid of
+// cols columns (a divisor of 12).
+func printYear(cols int, opts monthOptions, renderMonth func(m int, opts monthOptions)) {
+	for row := 0; row < 12/cols; row++ {
+		printYearRow(row*cols+1, cols, opts, renderMonth)
+	}
+}
+
+// printYearRow prints cols months side by side, starting with month first.
+func printYearRow(first, cols int, opts monthOptions, renderMonth func(m int, opts monthOptions)) {
+	renders := make([]func(opts monthOptions), cols)
+	for col := range renders {
+		m := first + col
+		renders[col] = func(opts monthOptions) { renderMonth(m, opts) }
+	}
+	printMonthColumns(renders, opts)
+}
+
+// printMonthColumns prints the months drawn by renders side by side,
+// separated by yearGap, then a blank line. Each month is captured from stdout
+// without its trailing blank line and padded to the month width and to the
+// height of the tallest one so that the columns line up.
+func printMonthColumns(renders []func(opts monthOptions), opts monthOptions) {
+	opts.NoTrailingNewline = true
+	width := opts.monthWidth()
+	monthLines := make([][]string, len(renders))
+	maxLines := 0
+	for col, render := range renders {
+		out := captureStdout(func() { render(opts) })
+		lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+		for i, line := range lines {
+			if visible := visibleWidth(line); visible < width {
+				lines[i] = line + strings.Repeat(" ", width-visible)
+			}
+		}
+		monthLines[col] = lines
+		if len(lines) > maxLines {
+			maxLines = len(lines)
 		}
 	}
-	if !found {
+	// The rows are joined in one buffer and printed at once rather than
+	// with a write per cell.
+	blank := strings.Repeat(" ", width)
+	var out strings.Builder
+	out.Grow(maxLines * len(renders) * (width + len(yearGap)))
+	for i := 0; i < maxLines; i++ {
+		for col, lines := range monthLines {
+			if col > 0 {
+				out.WriteString(yearGap)
+			}
+			if i < len(lines) {
+				out.WriteString(lines[i])
+			} else {
+				out.WriteString(blank)
+			}
+		}
+		out.WriteString("\n")
+	}
+	out.WriteString("\n")
+	_, err := fmt.Print(out.String())
+	checkStdout(err)
+}
+
+func printHolidaysOfMonth(jy, jm int, holidays *shamsy.HolidayCalendar) {
+	fmt.Println(withIcon("📌", "Holidays in this month:"))
+	entries := holidays.HolidaysIn(jy, jm)
+	for _, h := range entries {
+		fmt.Printf("- %02d %s: %s\n", h.Date.Day, shamsyMonths[jm-1], listedHolidayText(h.Name))
+	}
+	if len(entries) == 0 {
 		fmt.Println("No holidays in this month.")
 	}
+	// The month summary already carries the note.
+	if noSummary {
+		printFixedOnlyNote()
+	}
 }
 
-func printGregorianHolidaysOfMonth(year, month int, shamsyHolidays map[string]string) {
-	fmt.Println("📌 Holidays in this month:")
+func printGregorianHolidaysOfMonth(year, month int, shamsyHolidays *shamsy.HolidayCalendar) {
+	fmt.Println(withIcon("📌", "Holidays in this month:"))
 	found := false
 	for d := 1; d <= gregorianMonthDays(year, month); d++ {
-		jy, jm, jd := gregorianToshamsy(year, month, d)
-		key := fmt.Sprintf("%d-%02d-%02d", jy, jm, jd)
-		if desc, ok := shamsyHolidays[key]; ok {
+		jy, jm, jd := fromGregorian(year, month, d)
+		if desc, ok := shamsyHolidays.IsGregorianHoliday(year, month, d); ok {
+			desc = listedHolidayText(desc)
+			if gregorianEvents {
+				desc += " [IR official]"
+			}
 			fmt.Printf("- %02d %s: %s (Shamsi: %d/%d/%d)\n", d, gregorianMonths[month-1], desc, jy, jm, jd)
 			found = true
 		}
+		if name, ok := lookupObservance(month, d); ok && gregorianEvents {
+			fmt.Printf("- %02d %s: %s [observance] (Shamsi: %d/%d/%d)\n", d, gregorianMonths[month-1], name, jy, jm, jd)
+			found = true
+		}
 	}
 	if !found {
 		fmt.Println("No holidays in this month.")
 	}
+	// The month summary already carries the note.
+	if noSummary {
+		printFixedOnlyNote()
+	}
 }
 
-func getWeekdayName(gy, gm, gd int) string {
-	t := time.Date(gy, time.Month(gm), gd, 0, 0, 0, 0, time.UTC)
-	shamsyWeekdays := []string{"Saturday", "Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday"}
-	return shamsyWeekdays[goToshamsyWeekday[int(t.Weekday())]]
-}
+// dateSeparator separates the components of a date: one of "-", "." or "/",
+// optionally surrounded by spaces, or just spaces.
+var dateSeparator = regexp.MustCompile(`\s*[-./]\s*|\s+`)
 
+// parseDate splits a date such as 1403/09/05, 1403-9-5, "1403 9 5" or
+// "1403 / 9 / 5" into its numbers. Surrounding whitespace is ignored, but
+// empty components such as in 1403//5 are rejected.
 func parseDate(dateStr string) (int, int, int, error) {
-	dateStr = strings.ReplaceAll(dateStr, "-", "/")
-	dateStr = strings.ReplaceAll(dateStr, ".", "/")
-	parts := strings.Split(dateStr, "/")
+	parts := dateSeparator.Split(strings.TrimSpace(dateStr), -1)
 	if len(parts) != 3 {
-		return 0, 0, 0, fmt.Errorf("invalid date format, expected YYYY/MM/DD, YYYY-MM-DD, or YYYY.MM.DD")
+		return 0, 0, 0, withCode(codeInvalidDate, fmt.Errorf("invalid date format, expected YYYY/MM/DD, YYYY-MM-DD, or YYYY.MM.DD"))
 	}
 	year, err1 := strconv.Atoi(parts[0])
 	month, err2 := strconv.Atoi(parts[1])
 	day, err3 := strconv.Atoi(parts[2])
 	if err1 != nil || err2 != nil || err3 != nil {
-		return 0, 0, 0, fmt.Errorf("invalid date values")
+		return 0, 0, 0, withCode(codeInvalidDate, fmt.Errorf("invalid date values"))
 	}
-	if month < 1 || month > 12 || day < 1 || day > 31 {
-		return 0, 0, 0, fmt.Errorf("date out of range")
+	if year < 1 || year > maxYear || month < 1 || month > 12 || day < 1 || day > 31 {
+		return 0, 0, 0, withCode(codeInvalidDate, fmt.Errorf("date out of range"))
 	}
 	return year, month, day, nil
 }
 
-func handleConvertDate(dateStr string, isGregorian bool) error {
+// parseCalendarDate parses and validates a Shamsi date, or a Gregorian one
+// with isGregorian, and returns it in both calendars. Week dates such as
+// 1404-W28-1 or, with isGregorian, 2025-W40-6 are accepted too, and so are
+// ISO week dates written gweek:2025/14/3 in either mode.
+func parseCalendarDate(dateStr string, isGregorian bool) (int, int, int, shamsy.Date, error) {
+	if gy, gm, gd, date, ok, err := parseGWeek(strings.TrimSpace(dateStr)); ok {
+		return gy, gm, gd, date, err
+	}
+	if gy, gm, gd, date, ok, err := parseWeekDate(strings.TrimSpace(dateStr), isGregorian); ok {
+		return gy, gm, gd, date, err
+	}
 	year, month, day, err := parseDate(dateStr)
+	if err != nil {
+		return 0, 0, 0, shamsy.Date{}, err
+	}
+	if isGregorian {
+		if n := gregorianMonthDays(year, month); day > n {
+			return 0, 0, 0, shamsy.Date{}, withCode(codeInvalidDate, fmt.Errorf("invalid Gregorian date: %s %d has %d days", gregorianMonths[month-1], year, n))
+		}
+		if !shamsy.GregorianInRange(year, month, day) {
+			return 0, 0, 0, shamsy.Date{}, withCode(codeInvalidDate, fmt.Errorf("%s is outside the supported range %s", dateStr, supportedRange(true)))
+		}
+		return year, month, day, dateFromGregorian(year, month, day), nil
+	}
+	if n := shamsy.MonthDays(year, month); day > n {
+		return 0, 0, 0, shamsy.Date{}, withCode(codeInvalidDate, fmt.Errorf("invalid Shamsi date: %s %d has %d days", shamsyMonths[month-1], year, n))
+	}
+	if !shamsy.InRange(year) {
+		return 0, 0, 0, shamsy.Date{}, withCode(codeInvalidDate, fmt.Errorf("%s is outside the supported range %s", dateStr, supportedRange(false)))
+	}
+	gy, gm, gd := toGregorian(year, month, day)
+	return gy, gm, gd, shamsy.Date{Year: year, Month: month, Day: day}, nil
+}
+
+func handleConvertDate(dateStr string, isGregorian bool) error {
+	gy, gm, gd, date, err := parseCalendarDate(dateStr, isGregorian)
 	if err != nil {
 		return err
 	}
+	year, month, day := date.Year, date.Month, date.Day
+	if isGregorian {
+		year, month, day = gy, gm, gd
+	}
 	fmt.Println(rgb(cyan, strings.Repeat("=", 60)))
 	if isGregorian {
-		fmt.Println(rgb(purple, "📅 Converting Gregorian to Shamsi"))
+		fmt.Println(rgb(purple, withIcon("📅", "Converting Gregorian to Shamsi")))
 		fmt.Println(rgb(cyan, strings.Repeat("-", 60)))
-		if month > 12 || day > gregorianMonthDays(year, month) {
-			return fmt.Errorf("invalid Gregorian date")
-		}
-		jy, jm, jd := gregorianToshamsy(year, month, day)
-		weekday := getWeekdayName(year, month, day)
+		sh := shamsiDateInfo(year, month, day)
 		fmt.Printf("%s: %s\n", rgb(green, "Input (Gregorian)"),
-			rgb(blue, fmt.Sprintf("%04d/%02d/%02d - %s %d, %d", year, month, day, gregorianMonths[month-1], day, year)))
-		fmt.Printf("%s: %s\n", rgb(green, "Output (Shamsi)"),
-			rgb(yellow, fmt.Sprintf("%04d/%02d/%02d - %d %s %d", jy, jm, jd, jd, shamsyMonths[jm-1], jy)))
-		fmt.Printf("%s: %s\n", rgb(green, "Day of Week"), rgb(cyan, weekday))
-		holidays, err := fetchHolidays(jy)
+			rgb(blue, shamsy.FormatGregorian(year, month, day, "YYYY/MM/DD - MonthName D, YYYY")))
+		fmt.Printf("%s: %s%s\n", rgb(green, "Output (Shamsi)"),
+			rgb(yellow, shamsy.FormatShamsi(sh.Year, sh.Month, sh.Day, "YYYY/MM/DD - D MonthName YYYY")),
+			mismatchNote(gregorianMismatch(year, month, day)))
+		fmt.Printf("%s: %s\n", rgb(green, "Day of Week"), rgb(cyan, sh.DayWeek))
+		printWeekDates(date, gy, gm, gd)
+		holidays, err := fetchMonthHolidays(sh.Year, sh.Month)
 		if err == nil {
-			key := fmt.Sprintf("%d-%02d-%02d", jy, jm, jd)
-			if desc, ok := holidays[key]; ok {
-				fmt.Printf("%s: %s\n", rgb(green, "Holiday"), rgb(offday, desc))
+			if desc, ok := holidays.IsGregorianHoliday(year, month, day); ok {
+				fmt.Printf("%s: %s\n", rgb(green, "Holiday"), rgb(offday, holidayText(desc)))
 			}
 		}
 	} else {
-		fmt.Println(rgb(purple, "📅 Converting Shamsi to Gregorian"))
+		fmt.Println(rgb(purple, withIcon("📅", "Converting Shamsi to Gregorian")))
 		fmt.Println(rgb(cyan, strings.Repeat("-", 60)))
-		if month > 12 || day > shamsyMonthDays(year, month) {
-			return fmt.Errorf("invalid Shamsi date")
-		}
-		gy, gm, gd := shamsyToGregorian(year, month, day)
-		weekday := getWeekdayName(gy, gm, gd)
+		g := gregorianDateInfo(year, month, day)
 		fmt.Printf("%s: %s\n", rgb(green, "Input (Shamsi)"),
-			rgb(yellow, fmt.Sprintf("%04d/%02d/%02d - %d %s %d", year, month, day, day, shamsyMonths[month-1], year)))
-		fmt.Printf("%s: %s\n", rgb(green, "Output (Gregorian)"),
-			rgb(blue, fmt.Sprintf("%04d/%02d/%02d - %s %d, %d", gy, gm, gd, gregorianMonths[gm-1], gd, gy)))
-		fmt.Printf("%s: %s\n", rgb(green, "Day of Week"), rgb(cyan, weekday))
-		holidays, err := fetchHolidays(year)
+			rgb(yellow, shamsy.FormatShamsi(year, month, day, "YYYY/MM/DD - D MonthName YYYY")))
+		fmt.Printf("%s: %s%s\n", rgb(green, "Output (Gregorian)"),
+			rgb(blue, shamsy.FormatGregorian(g.Year, g.Month, g.Day, "YYYY/MM/DD - MonthName D, YYYY")),
+			mismatchNote(shamsyMismatch(year, month, day)))
+		fmt.Printf("%s: %s\n", rgb(green, "Day of Week"), rgb(cyan, g.DayWeek))
+		printWeekDates(date, gy, gm, gd)
+		holidays, err := fetchMonthHolidays(year, month)
 		if err == nil {
-			key := fmt.Sprintf("%d-%02d-%02d", year, month, day)
-			if desc, ok := holidays[key]; ok {
-				fmt.Printf("%s: %s\n", rgb(green, "Holiday"), rgb(offday, desc))
+			if desc, ok := holidays.IsHoliday(shamsy.Date{Year: year, Month: month, Day: day}); ok {
+				fmt.Printf("%s: %s\n", rgb(green, "Holiday"), rgb(offday, holidayText(desc)))
 			}
 		}
 	}
@@ -547,236 +601,875 @@ func handleConvertDate(dateStr string, isGregorian bool) error {
 	return nil
 }
 
+// maxYear is the largest year accepted by parseDate. The conversions
+// support a narrower range; see yearInRange.
+const maxYear = 9999
+
+// yearInRange reports whether a whole year of the selected calendar lies
+// within the range supported by the conversions.
+func yearInRange(y int, isGregorian bool) bool {
+	if isGregorian {
+		return shamsy.GregorianInRange(y, 1, 1) && shamsy.GregorianInRange(y, 12, 31)
+	}
+	return shamsy.InRange(y)
+}
+
+// supportedRange describes the supported dates of the selected calendar.
+func supportedRange(isGregorian bool) string {
+	if isGregorian {
+		sy, sm, sd := toGregorian(shamsy.MinYear, 1, 1)
+		ey, em, ed := toGregorian(shamsy.MaxYear, 12, shamsy.MonthDays(shamsy.MaxYear, 12))
+		return shamsy.FormatGregorian(sy, sm, sd, "YYYY/MM/DD") + "–" + shamsy.FormatGregorian(ey, em, ed, "YYYY/MM/DD")
+	}
+	return fmt.Sprintf("%d/01/01–%d/12/%02d", shamsy.MinYear, shamsy.MaxYear, shamsy.MonthDays(shamsy.MaxYear, 12))
+}
+
+// parseInterspersed parses fs from args while allowing flags to appear after
+// positional arguments (e.g. "fiscal 1404 --date 1404/05/10"). It returns the
+// positional arguments in order.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// parseCommandLine parses the global flags in fs from args and returns the
+// positional arguments. Flags may also follow the year and month, e.g.
+// "1404 --show-holidays 7", and unknown "--" tokens are reported instead of
+// being taken for a year or month. When the first positional argument is a
+// subcommand, the arguments after it are left to the subcommand's own flags.
+func parseCommandLine(fs *flag.FlagSet, args []string, isCommand func(name string) bool) ([]string, error) {
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 && isCommand(fs.Arg(0)) {
+		return fs.Args(), nil
+	}
+	return parseInterspersed(fs, fs.Args())
+}
+
 func main() {
+	// Parse errors are reported like other invalid arguments, by fail.
+	flag.CommandLine.Init(os.Args[0], flag.ContinueOnError)
 	useGregorian := flag.Bool("gregorian", false, "Use Gregorian calendar instead of Shamsi")
 	flag.BoolVar(useGregorian, "g", false, "Use Gregorian calendar (shorthand)")
 	convertDateFlag := flag.String("convert", "", "Convert date between calendars (format: YYYY/MM/DD or YYYY-MM-DD)")
 	flag.StringVar(convertDateFlag, "c", "", "Convert date (shorthand)")
+	card := flag.Bool("card", false, "With --convert, print the result as a card with the target month")
+	formatsFlag := flag.String("formats", "", "With --convert, print the date in these comma-separated formats")
+	flag.BoolVar(&jsonOutput, "json", false, "Print JSON where supported (--formats) and errors as JSON")
+	flag.StringVar(&outputPath, "output", "", "Write the output to this file instead of stdout")
+	flag.StringVar(&outputPath, "o", "", "Write the output to this file (shorthand)")
+	flag.BoolVar(&outputForce, "force", false, "With --output, overwrite an existing file")
+	flag.BoolVar(&outputMkdir, "mkdir", false, "With --output, create missing parent directories")
+	showHolidays := flag.Bool("show-holidays", false, "List the holidays of the selected month")
+	holidaysBetweenFlag := flag.String("holidays-between", "", "List holidays from this Shamsi date to the date given as argument")
+	prevFlag := flag.String("prev", "", "Show the month N months before the current one")
+	nextFlag := flag.String("next", "", "Show the month N months after the current one")
+	highlightFlag := flag.String("highlight", "", "Highlight this day of the month shown instead of today, or none")
+	agendaFlag := flag.String("agenda", "", "List the next N days from today with their holidays")
+	compareFlag := flag.String("compare", "", "Compare this Shamsi year with the one given as argument")
+	stripFlag := flag.String("strip", "", "Show N days from this date, given as argument, in a horizontal strip")
+	rangeConvertFlag := flag.String("range-convert", "", "Convert every day from this date to the date given as argument")
+	csvOutput := flag.Bool("csv", false, "With --range-convert, print CSV")
+	weekdaySeriesFlag := flag.String("weekday-series", "", "Show the weekday of MM/DD for each year from FROM to TO")
+	checkDateFlag := flag.String("check-date", "", "Exit with status 0 if DATE is valid, 1 with the reason otherwise")
+	sinceEpochFlag := flag.String("since-epoch", "", "Print the number of days from 1 Farvardin 1 to DATE")
+	fromEpochFlag := flag.String("from-epoch", "", "Print the date that is N days after 1 Farvardin 1")
+	weekRowsFlag := flag.String("week-rows", "", "Print how many week rows the month of DATE occupies (4, 5 or 6)")
+	explainFlag := flag.String("explain", "", "Show the intermediate steps of converting a date")
+	remainingInMonth := flag.Bool("remaining-in-month", false, "Print how many days are left in the current month")
+	remainingInYear := flag.Bool("remaining-in-year", false, "Print how many days are left in the current year")
+	ncal := flag.Bool("ncal", false, "Transposed layout with weekdays as rows (like ncal)")
+	halfDayFlag := flag.String("half-day", "", "Color these comma-separated weekdays as half-days (e.g. thu)")
+	padAdjacent := flag.Bool("pad-adjacent", false, "Show the neighbouring months' days, dimmed, in a single month's blank cells")
+	markFlag := flag.String("mark", "", "Color these comma-separated dates (Shamsi, or Gregorian with g:)")
+	markerFlag := flag.String("marker", "", "Print this character after the number of every holiday")
+	weekdayLangFlag := flag.String("weekday-lang", "en", "Language of the weekday header row: en or fa")
+	monthLangFlag := flag.String("month-lang", "en", "Language of the month titles: en or fa")
+	weekNumbers := flag.Bool("week-numbers", false, "With -g, show ISO week numbers in a leftmost column")
+	weekStartFlag := flag.String("week-start", "sunday", "First weekday of the Gregorian view")
+	weekstartSunday := flag.Bool("weekstart-sunday", false, "Start Gregorian weeks on Sunday (overrides --week-start)")
+	flag.BoolVar(&rainbowWeekdays, "rainbow-weekdays", false, "Give each weekday column its own color")
+	quarterGrid := flag.Bool("quarter-grid", false, "Group the year view into fiscal quarter rows")
+	flag.BoolVar(quarterGrid, "fiscal", false, "Group the year view into fiscal quarter rows (alias)")
+	noHeader := flag.Bool("no-header", false, "Omit the month title line")
+	noWeekdayHeader := flag.Bool("no-weekday-header", false, "Omit the weekday names row, keeping the day positions")
+	formatFlag := flag.String("format", "", "Output format of month and year views: plain (stable, ASCII only)")
+	showLength := flag.Bool("show-length", false, "Show the number of days of each month under its title")
+	bare := flag.Bool("bare", false, "Print only the day numbers: no title, weekday names, summary or trailing blank line")
+	gapFlag := flag.Int("gap", 4, "Spaces between the month columns of the year view")
+	separatorFlag := flag.String("separator", "", "Character drawn between the month columns of the year view")
+	strictWidth := flag.Bool("strict-width", false, "Fail instead of adapting the layout when the terminal is too narrow")
+	noTrailingNewline := flag.Bool("no-trailing-newline", false, "Omit the blank line after each month")
+	flag.BoolVar(&emojiHolidays, "emoji-holidays", false, "Prefix listed holidays with an icon")
+	flag.BoolVar(&asciiOutput, "ascii", false, "Leave out the emoji of titles and holiday listings")
+	flag.BoolVar(&translateHolidays, "translate", false, "Show English names of official holidays")
+	flag.BoolVar(&observedMode, "observed", false, "Move holidays falling on a Friday to the next working day")
+	flag.BoolVar(&renderCacheEnabled, "render-cache", false, "Cache rendered month views for repeated invocations")
+	flag.BoolVar(&noSummary, "no-summary", false, "Omit the summary printed after a single month")
+	lightFlag := flag.Bool("light", false, "Use the palette for light terminal backgrounds")
+	darkFlag := flag.Bool("dark", false, "Use the palette for dark terminal backgrounds")
+	flag.BoolVar(&noColor, "no-color", false, "Print without colors (also set by NO_COLOR)")
+	colorDepthFlag := flag.String("color-depth", "", "Colors to emit: truecolor, 256, 16 or none (default: detected from $COLORTERM and $TERM)")
+	configFlag := flag.String("config", "", "Read settings from this file instead of the default config.json")
+	profileFlag := flag.String("profile", "", "Apply the settings of this profile of the config")
+	saveConfigFlag := flag.Bool("save-config", false, "Save the other flags given as defaults in the config file")
+	apiURL := flag.String("api-url", shamsy.DefaultAPIURL, "Holiday API endpoint")
+	tzFlag := flag.String("tz", defaultTimeZone, "IANA time zone whose date is today, e.g. Europe/Berlin or Local")
+	regionFlag := flag.String("region", shamsy.DefaultRegion, "Region whose holidays are shown")
+	flag.StringVar(&holidayOptions.CacheDir, "cache-dir", "", "Directory for cached holidays and views (default $SHAMSY_CACHE_DIR or the user cache directory)")
+	flag.DurationVar(&fetchTimeout, "timeout", 0, "Give up fetching holidays after this long (e.g. 10s)")
+	flag.BoolVar(&holidayOptions.NoCache, "no-cache", false, "Fetch holidays without reading or writing the cache")
+	flag.BoolVar(&holidayOptions.NoCacheWrite, "no-cache-write", false, "Use cached holidays but do not cache fetched ones")
+	flag.BoolVar(&holidayOptions.Refresh, "refresh", false, "Fetch holidays again and replace the cached ones")
+	flag.BoolVar(&holidayOptions.StrictCache, "strict-cache", false, "Fail when fetched holidays cannot be cached")
+	flag.BoolVar(&forecastMode, "forecast", false, "Show estimated holidays for years without holiday data")
+	flag.BoolVar(&fixedOnly, "fixed-only", false, "Use only the built-in fixed holidays; no network or cache")
+	flag.BoolVar(&forceYear, "force-year", false, "Accept a year below the typo threshold (e.g. 87) without asking")
+	flag.BoolVar(&noHistory, "no-history", false, "Do not record this conversion in the history")
+	flag.BoolVar(&showWeekDates, "week-date", false, "Also print the Shamsi and ISO week dates with -c")
+	flag.BoolVar(&paranoid, "paranoid", false, "Cross-check every displayed date conversion and mark mismatches with !")
+	flag.BoolVar(&verbose, "verbose", false, "Print diagnostic messages to stderr")
+	versionFlag := flag.Bool("version", false, "Print the version and the holiday data bundle in use")
+	flag.BoolVar(&gregorianEvents, "gregorian-events", false, "Mark international observances in the Gregorian view")
 	flag.Usage = func() {
-		fmt.Println("Usage: shamsy-calendar [flags] [year] [month] [--show-holidays]")
+		fmt.Println("Usage: shamsy-calendar [flags] [year] [month] [flags]")
+		fmt.Println("       shamsy-calendar fiscal [year] [--date DATE]")
+		fmt.Println("       shamsy-calendar info month YEAR MONTH [--json]")
+		fmt.Println("       shamsy-calendar rules test YEAR/MONTH")
+		fmt.Println("       shamsy-calendar history [run N]")
+		fmt.Println("       shamsy-calendar holidays --from DATE --to DATE")
+		fmt.Println("       shamsy-calendar leaps FROM-TO [--gregorian] [--json]")
+		fmt.Println("       shamsy-calendar update-data [--url URL] [--verify-only]")
+		fmt.Println("       shamsy-calendar compare-month Y1/M Y2/M")
+		fmt.Println("       shamsy-calendar stats --weekdays YEAR [MONTH] [--json]")
+		fmt.Println("       shamsy-calendar motd [--width N] [--color always|never]")
 		fmt.Println("\nFlags:")
 		fmt.Println("  -g, --gregorian              Use Gregorian calendar instead of Shamsi")
+		fmt.Println("      --show-holidays          Show holidays for the selected month")
 		fmt.Println("  -c, --convert DATE           Convert date between calendars")
-		fmt.Println("                               Format: YYYY/MM/DD, YYYY-MM-DD, or YYYY.MM.DD")
+		fmt.Println("                               Format: YYYY/MM/DD, YYYY-MM-DD, YYYY.MM.DD or YYYY MM DD")
+		fmt.Println("                               (spaces around separators and one-digit parts are fine)")
+		fmt.Println("                               DATE \"-\" reads a single date from stdin")
+		fmt.Println("                               or a week date: 1404-W28-1 (Shamsi weeks run Saturday to")
+		fmt.Println("                               Friday, week 1 contains 1 Farvardin) or, with -g, ISO")
+		fmt.Println("                               2025-W40-6 (Monday is day 1), or gweek:2025/14/3 for an")
+		fmt.Println("                               ISO week in either mode")
+		fmt.Println("      --week-date              With -c, also print the Shamsi and ISO week dates")
 		fmt.Println("                               Default: Shamsi to Gregorian")
 		fmt.Println("                               With -g: Gregorian to Shamsi")
+		fmt.Println("      --card                   With -c, show the result in a 40-column box with the")
+		fmt.Println("                               converted day highlighted in its month")
+		fmt.Println("      --formats LIST           With -c, print the date in each listed format:")
+		fmt.Println("                               iso (Gregorian YYYY-MM-DD), shamsi (YYYY/MM/DD),")
+		fmt.Println("                               hijri (tabular Islamic calendar), jdn (Julian Day Number),")
+		fmt.Println("                               epoch (days since 1 Farvardin 1), gweek (ISO week as")
+		fmt.Println("                               YEAR/WEEK/DAY, Monday is day 1)")
+		fmt.Println("      --json                   With --formats, print a single JSON object; with")
+		fmt.Println("                               --show-holidays YEAR MONTH, an array of the month's")
+		fmt.Println("                               holidays ({date, gregorian, weekday, event}); with")
+		fmt.Println("                               --range-convert, an array of {shamsi, gregorian, weekday};")
+		fmt.Println("                               errors are printed to stdout as")
+		fmt.Println("                               {\"error\": {\"code\", \"message\"}}")
+		fmt.Println("  -o, --output FILE            Write the output to FILE instead of stdout, without colors;")
+		fmt.Println("                               the file only appears once complete, and warnings stay")
+		fmt.Println("                               on stderr")
+		fmt.Println("      --force                  With -o, overwrite an existing FILE")
+		fmt.Println("      --mkdir                  With -o, create FILE's missing parent directories")
+		fmt.Println("      --holidays-between FROM TO")
+		fmt.Println("                               List holidays between two Shamsi dates, across years")
+		fmt.Println("      --prev N, --next N       Show the month N months before or after the current one")
+		fmt.Println("      --highlight DAY|none     Highlight DAY of the month shown instead of today, or no")
+		fmt.Println("                               day with none, e.g. scal 1404 6 --highlight 15")
+		fmt.Println("                               (Gregorian months with -g)")
+		fmt.Println("      --agenda N               List the next N days from today, one per line, with both")
+		fmt.Println("                               dates, the weekday and any holiday (observances too with")
+		fmt.Println("                               --gregorian-events)")
+		fmt.Println("      --compare Y1 Y2          Compare two Shamsi years: leap status, length, Nowruz,")
+		fmt.Println("                               holiday count and the holidays that moved")
+		fmt.Println("      --strip START N          Show N days from START (Gregorian with -g, or today) in a")
+		fmt.Println("                               horizontal strip under their weekdays, e.g. a sprint:")
+		fmt.Println("                               --strip 1404/07/26 14")
+		fmt.Println("      --range-convert FROM TO  Print every day from FROM to TO with its Gregorian date and")
+		fmt.Println("                               weekday (Gregorian FROM and TO with -g); --csv or --json")
+		fmt.Println("                               for machine-readable tables")
+		fmt.Println("      --weekday-series MM/DD FROM TO")
+		fmt.Println("                               Show the weekday MM/DD falls on in each year")
+		fmt.Println("      --check-date DATE        Validate DATE (Gregorian with -g), including month lengths")
+		fmt.Println("                               and leap years: silent exit 0 if valid, otherwise exit 1")
+		fmt.Println("                               with the reason on stderr")
+		fmt.Println("      --since-epoch DATE       Print the number of days from 1 Farvardin 1 to DATE")
+		fmt.Println("                               (Gregorian DATE with -g), a sortable integer key")
+		fmt.Println("      --from-epoch N           Print the Shamsi date N days after 1 Farvardin 1")
+		fmt.Println("                               (the Gregorian date with -g)")
+		fmt.Println("      --week-rows DATE         Print how many week rows (4, 5 or 6) the month of DATE")
+		fmt.Println("                               occupies in the grid (Gregorian DATE with -g, laid out")
+		fmt.Println("                               with --week-start)")
+		fmt.Println("      --explain DATE           Show how DATE is converted, step by step")
+		fmt.Println("                               (Gregorian input with -g)")
+		fmt.Println("      --remaining-in-month     Print how many days are left in this month after today")
+		fmt.Println("      --remaining-in-year      Print how many days are left in this year after today")
+		fmt.Println("                               (Gregorian with -g)")
+		fmt.Println("      --ncal                   Transposed layout: weekdays as rows, weeks as columns")
+		fmt.Println("      --quarter-grid, --fiscal Show the year as four fiscal quarter rows")
+		fmt.Println("                               (Bahar, Tabestan, Paeez, Zemestan)")
+		fmt.Println("      --half-day DAYS          Color the given weekdays as half working days, e.g.")
+		fmt.Println("                               --half-day thu or --half-day Pa (names or prefixes)")
+		fmt.Println("      --week-start DAY         First weekday of the Gregorian view (default sunday),")
+		fmt.Println("                               e.g. --week-start monday")
+		fmt.Println("      --pad-adjacent           Fill the blank cells of a single month with the days of")
+		fmt.Println("                               the previous and next month, dimmed (not in year views)")
+		fmt.Println("      --mark DATES             Color the listed days in the calendar: Shamsi dates, or")
+		fmt.Println("                               Gregorian ones prefixed with g:, in either view, e.g.")
+		fmt.Println("                               --mark 1404/07/12,g:2025-10-04")
+		fmt.Println("      --marker CHAR            Mark holidays with CHAR after the day number, e.g. --marker '*'")
+		fmt.Println("                               (useful with --no-color); cells widen to fit it")
+		fmt.Println("      --weekday-lang en|fa     Weekday header in transliterated English (default) or")
+		fmt.Println("                               Persian letters (ش ی د س چ پ ج)")
+		fmt.Println("      --month-lang en|fa       Month titles in transliterated English (default) or Persian")
+		fmt.Println("      --week-numbers           With -g, prefix each week with its ISO 8601 week number")
+		fmt.Println("      --weekstart-sunday       Start Gregorian weeks on Sunday, overriding --week-start")
+		fmt.Println("      --rainbow-weekdays       Tint each weekday column with its own color")
+		fmt.Println("      --gregorian-events       With -g, mark and list international observances")
+		fmt.Println("      --translate              Show English names of official holidays")
+		fmt.Println("      --emoji-holidays         Prefix the holidays listed by --show-holidays with an")
+		fmt.Println("                               icon (🌱 Nowruz, 🌙 Eid al-Fitr, ...; 📌 otherwise)")
+		fmt.Println("      --ascii                  Leave out the emoji of titles and holiday listings")
+		fmt.Println("      --observed               Also mark the next working day of holidays that fall")
+		fmt.Println("                               on a Friday (affects working-day counts)")
+		fmt.Println("      --render-cache           Reuse the rendered month from the cache when nothing")
+		fmt.Println("                               changed (for prompts and status bars)")
+		fmt.Println("      --cache-dir DIR          Keep cached holidays and views in DIR (default:")
+		fmt.Println("                               $SHAMSY_CACHE_DIR, else $XDG_CACHE_HOME/shamsy_calendar")
+		fmt.Println("                               or the system cache directory)")
+		fmt.Println("      --config FILE            Read settings and rules from FILE (default:")
+		fmt.Println("                               config.json in the user config directory)")
+		fmt.Println("      --profile NAME           Apply the settings of profile NAME of the config, e.g.")
+		fmt.Println("                               its region, languages and time zone")
+		fmt.Println("      --save-config            Save the setting flags given with it as defaults in the")
+		fmt.Println("                               config file, e.g. scal -g --week-start monday --save-config")
+		fmt.Println("      --api-url URL            Fetch holidays from URL instead of the pnldev.com API")
+		fmt.Println("                               (any server returning the same JSON shape)")
+		fmt.Println("      --tz ZONE                Take today's date in the IANA time zone ZONE (default:")
+		fmt.Println("                               Asia/Tehran; \"Local\" for the system time zone)")
+		fmt.Println("      --region CODE            Show the holidays of region CODE (default: ir, the only")
+		fmt.Println("                               one so far)")
+		fmt.Println("      --timeout DURATION       Give up fetching holidays after DURATION, e.g. 10s")
+		fmt.Println("                               (Ctrl-C also cancels a fetch cleanly)")
+		fmt.Println("      --no-cache               Always fetch holidays, neither reading nor writing the cache")
+		fmt.Println("                               (e.g. to test API changes without touching the cache)")
+		fmt.Println("      --no-cache-write         Use cached holidays, but do not cache newly fetched ones")
+		fmt.Println("      --refresh                Fetch holidays again and replace the cached ones")
+		fmt.Println("      --strict-cache           Exit with an error instead of a warning when fetched")
+		fmt.Println("                               holidays cannot be written to the cache (for CI)")
+		fmt.Println("      --fixed-only             Use only the built-in table of fixed national holidays")
+		fmt.Println("                               and Fridays, without network or cache; movable")
+		fmt.Println("                               religious holidays are excluded (deterministic, for CI)")
+		fmt.Println("      --forecast               Show the fixed and estimated lunar holidays, in their own")
+		fmt.Println("                               color, for years whose holidays cannot be loaded")
+		fmt.Println("      --force-year             Accept a year argument below 1000 (Gregorian: 1500), which")
+		fmt.Println("                               is otherwise taken for a typo such as 87 for 1387")
+		fmt.Println("      --no-history             Do not record this -c conversion in the history")
+		fmt.Println("      --paranoid               Convert every displayed date back as a cross-check; days")
+		fmt.Println("                               that do not round-trip are marked with ! and reported")
+		fmt.Println("      --verbose                Print diagnostics, e.g. holidays with no translation and")
+		fmt.Println("                               the checks of holiday API responses")
+		fmt.Println("      --no-summary             Omit the working-day and holiday summary after a month")
+		fmt.Println("      --light, --dark          Pick the palette for a light or dark terminal background")
+		fmt.Println("                               (detected from COLORFGBG when not given)")
+		fmt.Println("      --no-color               Print without colors (also enabled by the NO_COLOR")
+		fmt.Println("                               environment variable)")
+		fmt.Println("      --color-depth DEPTH      Emit truecolor, 256, 16 or none colors; by default")
+		fmt.Println("                               detected from $COLORTERM and $TERM")
+		fmt.Println("      --no-header              Omit the \"==== Month Year ====\" title line")
+		fmt.Println("      --format plain           Print month and year views in a stable, uncolored,")
+		fmt.Println("                               ASCII-only layout for snapshots: 3-character cells,")
+		fmt.Println("                               '*' after holidays, '#' after today")
+		fmt.Println("      --show-length            Show \"(N days)\" right-aligned under each month's title")
+		fmt.Println("      --no-weekday-header      Omit the weekday names row; days keep their columns")
+		fmt.Println("      --bare                   Print only the day numbers (no title, weekday names or")
+		fmt.Println("                               summary), e.g. for embedding")
+		fmt.Println("      --no-trailing-newline    Omit the blank line printed after a month")
+		fmt.Println("      --gap N                  Put N spaces between the month columns of the year")
+		fmt.Println("                               view (default 4)")
+		fmt.Println("      --separator CHAR         Draw CHAR between the month columns, e.g. \"│\"")
+		fmt.Println("      --strict-width           Fail when the terminal is too narrow instead of")
+		fmt.Println("                               using fewer year columns or the mini month layout")
+		fmt.Println("      --version                Print the version and the data bundle in use")
 		fmt.Println("  -h, --help                   Show this help message and exit")
 		fmt.Println("\nArguments:")
 		fmt.Println("  year                         Year to display (Shamsi by default, Gregorian with -g)")
-		fmt.Println("  month                        Month to display (1-12)")
-		fmt.Println("  --show-holidays              Show holidays for the selected month")
+		fmt.Println("  month                        Month to display (1-12, or \"all\" for the whole year)")
+		fmt.Println("                               Flags may appear before or after the arguments.")
+		fmt.Println("\nCommands:")
+		fmt.Println("  fiscal [year] [--date DATE]  Show fiscal quarters with Gregorian dates and working days")
+		fmt.Println("                               --date reports the quarter of DATE (Gregorian with -g)")
+		fmt.Println("  info month YEAR MONTH        First weekday, length, leap status and Gregorian span")
+		fmt.Println("                               of a Shamsi month (--json for machine-readable output)")
+		fmt.Println("  rules test YEAR/MONTH        List the days of a Shamsi month matched by each rule")
+		fmt.Println("                               of the config file")
+		fmt.Println("  holidays --from DATE --to DATE")
+		fmt.Println("                               List the holidays between two Shamsi dates, across")
+		fmt.Println("                               years, with the number of off days (holidays plus Fridays)")
+		fmt.Println("  leaps FROM-TO                List the leap years of a range with the gap since the")
+		fmt.Println("                               previous one and the Gregorian date of 30 Esfand")
+		fmt.Println("                               (--gregorian or -g: Gregorian leap years; --json)")
+		fmt.Println("  history [run N]              List recent -c conversions, or repeat entry N")
+		fmt.Println("                               (recorded when the config sets \"history\": true)")
+		fmt.Println("  compare-month Y1/M Y2/M")
+		fmt.Println("                               Show a month of two years side by side with their holidays,")
+		fmt.Println("                               the weekday of each 1st and the holidays that differ")
+		fmt.Println("                               (Gregorian months with -g)")
+		fmt.Println("  stats --weekdays YEAR [MONTH]")
+		fmt.Println("                               Count each weekday of a Shamsi year or month, the holidays")
+		fmt.Println("                               on each and the weekday with the most holidays (--json)")
+		fmt.Println("  forecast YEAR                List the provisional holidays of a Shamsi year: the fixed")
+		fmt.Println("                               ones plus lunar ones estimated from the tabular Hijri")
+		fmt.Println("                               calendar, which may be 1-2 days off (--json)")
+		fmt.Println("  motd [--width N] [--color always|never]")
+		fmt.Println("                               Print the mini month and today's summary for /etc/motd:")
+		fmt.Println("                               holidays only from the cache, the text cached for the")
+		fmt.Println("                               day, wrapped to N columns (default 80), no colors")
+		fmt.Println("                               unless --color always")
+		fmt.Println("  update-data [--url URL] [--verify-only]")
+		fmt.Println("                               Download the holiday name, icon and fixed-holiday")
+		fmt.Println("                               tables from URL (default: \"data_url\" in the config),")
+		fmt.Println("                               check them against URL.sha256 and use them instead of")
+		fmt.Println("                               the built-in ones; --verify-only checks without installing")
+		fmt.Println("\nError codes (--json):")
+		fmt.Println("  invalid_date                 Malformed or nonexistent date")
+		fmt.Println("  invalid_argument             Bad year, month or option value")
+		fmt.Println("  usage                        Wrong number or combination of arguments")
+		fmt.Println("  holidays_unavailable         Holidays could not be fetched or read from the cache")
+		fmt.Println("  config_error                 Unreadable or invalid config file or rule")
+		fmt.Println("  error                        Anything else")
+		fmt.Println("  The exit status is 1 for every error.")
+		fmt.Println("\nEnvironment (only when run without arguments or flags):")
+		fmt.Println("  SHAMSY_DEFAULT_VIEW          month (default), today (one line) or week (current row)")
+		fmt.Println("  SHAMSY_DEFAULT_CALENDAR      shamsi (default) or gregorian")
 		fmt.Println("\nExamples:")
 		fmt.Println("  shamsy-calendar                           # Show current month (Shamsi)")
 		fmt.Println("  shamsy-calendar -g                        # Show current month (Gregorian)")
 		fmt.Println("  shamsy-calendar 1404                      # Show all months for Shamsi year 1404")
 		fmt.Println("  shamsy-calendar -g 2025                   # Show all months for Gregorian year 2025")
+		fmt.Println("  shamsy-calendar 1404 all                  # Same as above")
 		fmt.Println("  shamsy-calendar 1404 7                    # Show Shamsi month 7 of year 1404")
 		fmt.Println("  shamsy-calendar -g 2025 10                # Show Gregorian month 10 of year 2025")
 		fmt.Println("  shamsy-calendar 1404 7 --show-holidays    # Show holidays for Shamsi month")
+		fmt.Println("  shamsy-calendar --ncal 1404               # Show Shamsi year 1404 in ncal layout")
+		fmt.Println("  shamsy-calendar --quarter-grid 1404       # Show Shamsi year 1404 by fiscal quarter")
 		fmt.Println("\n  # Date conversion examples:")
 		fmt.Println("  shamsy-calendar -c 1403/09/15             # Convert Shamsi to Gregorian")
 		fmt.Println("  shamsy-calendar -c 1403-09-15             # Same as above (different separator)")
 		fmt.Println("  shamsy-calendar -g -c 2024/12/05          # Convert Gregorian to Shamsi")
 		fmt.Println("  shamsy-calendar -g -c 2024-12-05          # Same as above")
+		fmt.Println("  shamsy-calendar -c 1404/01/01 --formats iso,hijri,jdn")
+		fmt.Println("                                            # Several representations at once")
+		fmt.Println("  shamsy-calendar --holidays-between 1403/10/01 1404/03/31")
+		fmt.Println("                                            # Holidays across a year boundary")
+		fmt.Println("  shamsy-calendar --weekday-series 12/30 1400 1410")
+		fmt.Println("                                            # Weekday of 30 Esfand, leap years only")
+		fmt.Println("\n  # Fiscal year examples:")
+		fmt.Println("  shamsy-calendar fiscal 1404               # Quarters of fiscal year 1404")
+		fmt.Println("  shamsy-calendar fiscal --date 1404/05/10  # Which quarter 1404/05/10 falls in")
+	}
+	commands := map[string]func(args []string) error{
+		"fiscal":        func(args []string) error { return handleFiscal(args, *useGregorian) },
+		"info":          handleInfo,
+		"rules":         handleRules,
+		"history":       handleHistory,
+		"holidays":      handleHolidays,
+		"leaps":         func(args []string) error { return handleLeaps(args, *useGregorian) },
+		"update-data":   handleUpdateData,
+		"stats":         handleStats,
+		"forecast":      handleForecast,
+		"motd":          func(args []string) error { return handleMotd(args, *useGregorian) },
+		"compare-month": func(args []string) error { return handleCompareMonth(args, *useGregorian) },
+	}
+	args, parseErr := parseCommandLine(flag.CommandLine, os.Args[1:], func(name string) bool {
+		_, ok := commands[name]
+		return ok
+	})
+	if parseErr == flag.ErrHelp {
+		os.Exit(0)
+	}
+	if parseErr != nil {
+		fail(withCode(codeInvalidArgument, parseErr))
+	}
+	catchBrokenPipe()
+	defer setupFetchContext()()
+	defer func() {
+		if err := finishOutput(); err != nil {
+			fail(err)
+		}
+	}()
+	defer reportMismatches()
+	if err := applyConfig(*configFlag); err != nil {
+		fail(err)
+	}
+	if *saveConfigFlag {
+		if err := saveConfigFromFlags(*configFlag, args); err != nil {
+			fail(err)
+		}
+		return
+	}
+	if *profileFlag != "" {
+		if err := applyProfile(*profileFlag); err != nil {
+			fail(err)
+		}
+	}
+	if err := applyDefaults(); err != nil {
+		fail(err)
+	}
+	// --api-url only applies to the API behind the default region; other
+	// regions use their own providers.
+	holidayOptions.Region = strings.ToLower(*regionFlag)
+	if providers, err := shamsy.RegionProviders(holidayOptions.Region); err != nil {
+		fail(withCode(codeInvalidArgument, err))
+	} else if holidayOptions.Region == shamsy.DefaultRegion {
+		holidayOptions.Providers = []shamsy.Provider{shamsy.APIProvider{URL: *apiURL, OnCheck: reportSchemaCheck}}
+	} else {
+		holidayOptions.Providers = providers
+	}
+	setTimeZone(*tzFlag)
+	if *halfDayFlag != "" {
+		var err error
+		if halfDays, err = parseHalfDays(*halfDayFlag); err != nil {
+			fail(err)
+		}
+	}
+	if holidayOptions.CacheDir == "" {
+		holidayOptions.CacheDir = os.Getenv("SHAMSY_CACHE_DIR")
+	}
+	loadDataBundle()
+	if *versionFlag {
+		printVersion()
+		return
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		noColor = true
+	}
+	if depth, err := parseColorDepth(*colorDepthFlag); err != nil {
+		fail(err)
+	} else if colorDepth = depth; depth == depthNone {
+		noColor = true
+	}
+	if *lightFlag || (!*darkFlag && lightBackground()) {
+		useLightPalette()
 	}
-	flag.Parse()
-	args := flag.Args()
 	if len(args) > 0 && (args[0] == "-h" || args[0] == "--help" || args[0] == "help") {
 		flag.Usage()
 		os.Exit(0)
 	}
+	if len(os.Args) == 1 {
+		view, gregorian, err := defaultView()
+		if err != nil {
+			fail(err)
+		}
+		*useGregorian = gregorian
+		switch view {
+		case "today":
+			printTodayLine(gregorian)
+			return
+		case "week":
+			printCurrentWeek(gregorian)
+			return
+		}
+	}
+	if err := startOutput(); err != nil {
+		fail(err)
+	}
+	if len(args) > 0 {
+		if run, ok := commands[args[0]]; ok {
+			if err := run(args[1:]); err != nil {
+				fail(err)
+			}
+			return
+		}
+	}
+	var flagErr error
+	if *convertDateFlag == "-" {
+		if *convertDateFlag, flagErr = readStdinDate(); flagErr != nil {
+			fail(flagErr)
+		}
+	}
+	if *markFlag != "" {
+		if flagErr = parseMarks(*markFlag); flagErr != nil {
+			fail(flagErr)
+		}
+	}
+	if *markerFlag != "" {
+		if holidayMarker, markerWidth, flagErr = parseMarker(*markerFlag); flagErr != nil {
+			fail(flagErr)
+		}
+	}
+	if plainFormat, flagErr = parseFormat(*formatFlag); flagErr != nil {
+		fail(flagErr)
+	}
+	if plainFormat {
+		noColor, noSummary, asciiOutput = true, true, true
+	}
+	if yearGap, flagErr = parseYearGap(*gapFlag, *separatorFlag); flagErr != nil {
+		fail(flagErr)
+	}
+	if weekdayLang, flagErr = parseLang("weekday-lang", *weekdayLangFlag); flagErr != nil {
+		fail(flagErr)
+	}
+	if monthLang, flagErr = parseLang("month-lang", *monthLangFlag); flagErr != nil {
+		fail(flagErr)
+	}
+	if flagErr = parseHighlight(*highlightFlag); flagErr != nil {
+		fail(flagErr)
+	}
+	if *weekstartSunday {
+		*weekStartFlag = "sunday"
+	}
+	if ws, err := parseWeekStart(*weekStartFlag); err != nil {
+		fail(err)
+	} else {
+		gregorianWeekStart = ws
+	}
+	if *holidaysBetweenFlag != "" {
+		if len(args) != 1 {
+			fail(withCode(codeUsage, fmt.Errorf("--holidays-between needs an end date, e.g. --holidays-between 1403/10/01 1404/03/31")))
+		}
+		if err := handleHolidaysBetween(*holidaysBetweenFlag, args[0]); err != nil {
+			fail(err)
+		}
+		return
+	}
+	if *compareFlag != "" {
+		if len(args) != 1 {
+			fail(withCode(codeUsage, fmt.Errorf("--compare needs a second year, e.g. --compare 1403 1404")))
+		}
+		if err := handleCompareYears(*compareFlag, args[0]); err != nil {
+			fail(err)
+		}
+		return
+	}
+	if *stripFlag != "" {
+		if len(args) != 1 {
+			fail(withCode(codeUsage, fmt.Errorf("--strip needs a number of days, e.g. --strip 1404/07/26 14")))
+		}
+		if err := handleStrip(*stripFlag, args[0], *useGregorian); err != nil {
+			fail(err)
+		}
+		return
+	}
+	if *agendaFlag != "" {
+		if err := handleAgenda(*agendaFlag); err != nil {
+			fail(err)
+		}
+		return
+	}
+	if *rangeConvertFlag != "" {
+		if len(args) != 1 {
+			fail(withCode(codeUsage, fmt.Errorf("--range-convert needs an end date, e.g. --range-convert 1404/01/01 1404/01/10")))
+		}
+		if err := handleRangeConvert(*rangeConvertFlag, args[0], *useGregorian, *csvOutput); err != nil {
+			fail(err)
+		}
+		return
+	}
+	if *weekdaySeriesFlag != "" {
+		if len(args) != 2 {
+			fail(withCode(codeUsage, fmt.Errorf("--weekday-series needs a year range, e.g. --weekday-series 07/12 1403 1413")))
+		}
+		if err := handleWeekdaySeries(*weekdaySeriesFlag, args[0], args[1], *useGregorian); err != nil {
+			fail(err)
+		}
+		return
+	}
+	if *checkDateFlag != "" {
+		if _, _, _, _, err := parseCalendarDate(*checkDateFlag, *useGregorian); err != nil {
+			fail(err)
+		}
+		if verbose {
+			calendar := "Shamsi"
+			if *useGregorian {
+				calendar = "Gregorian"
+			}
+			fmt.Fprintf(os.Stderr, "%s is a valid %s date\n", *checkDateFlag, calendar)
+		}
+		return
+	}
+	if *sinceEpochFlag != "" {
+		if err := handleSinceEpoch(*sinceEpochFlag, *useGregorian); err != nil {
+			fail(err)
+		}
+		return
+	}
+	if *fromEpochFlag != "" {
+		if err := handleFromEpoch(*fromEpochFlag, *useGregorian); err != nil {
+			fail(err)
+		}
+		return
+	}
+	if *weekRowsFlag != "" {
+		if err := handleWeekRows(*weekRowsFlag, *useGregorian); err != nil {
+			fail(err)
+		}
+		return
+	}
+	if *explainFlag != "" {
+		if err := handleExplain(*explainFlag, *useGregorian); err != nil {
+			fail(err)
+		}
+		return
+	}
+	if *remainingInMonth || *remainingInYear {
+		handleRemaining(*remainingInMonth, *remainingInYear, *useGregorian)
+		return
+	}
+	if *convertDateFlag != "" && *formatsFlag != "" {
+		if err := handleFormats(*convertDateFlag, *useGregorian, *formatsFlag); err != nil {
+			fail(err)
+		}
+		recordConversion(*convertDateFlag, *useGregorian)
+		return
+	}
+	if *convertDateFlag != "" && *card {
+		if err := handleCard(*convertDateFlag, *useGregorian); err != nil {
+			fail(err)
+		}
+		recordConversion(*convertDateFlag, *useGregorian)
+		return
+	}
 	if *convertDateFlag != "" {
 		if err := handleConvertDate(*convertDateFlag, *useGregorian); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			fail(err)
 		}
+		recordConversion(*convertDateFlag, *useGregorian)
 		return
 	}
+	if *prevFlag != "" || *nextFlag != "" {
+		if len(args) > 0 {
+			fail(withCode(codeUsage, fmt.Errorf("--prev and --next cannot be combined with a year or month")))
+		}
+		if args, flagErr = relativeMonthArgs(*prevFlag, *nextFlag, *useGregorian); flagErr != nil {
+			fail(flagErr)
+		}
+	}
+	// "YEAR all" is an explicit request for the full-year view.
+	if len(args) == 2 && strings.EqualFold(args[1], "all") {
+		args = args[:1]
+	}
+	if *weekNumbers && (!*useGregorian || *ncal) {
+		fail(withCode(codeUsage, fmt.Errorf("--week-numbers is only available in the Gregorian grid (-g without --ncal)")))
+	}
+	if plainFormat && (*ncal || *quarterGrid || *showHolidays || *weekNumbers) {
+		fail(withCode(codeUsage, fmt.Errorf("--format plain cannot be combined with --ncal, --quarter-grid, --show-holidays or --week-numbers")))
+	}
+	if *bare {
+		*noHeader, *noWeekdayHeader, *noTrailingNewline, noSummary = true, true, true, true
+	}
+	monthOpts := monthOptions{NoHeader: *noHeader, NoWeekdayHeader: *noWeekdayHeader, NoTrailingNewline: *noTrailingNewline, WeekNumbers: *weekNumbers, PadAdjacent: *padAdjacent, ShowLength: *showLength}
+	layout := layoutGrid
+	switch {
+	case plainFormat:
+		layout = layoutPlain
+	case *ncal:
+		layout = layoutNcal
+	}
+	// holidays is loaded by each view below before its months are printed.
+	var holidays *shamsy.HolidayCalendar
+	printMonth := func(y, m, highlight int, opts monthOptions) {
+		renderMonth(renderOptions{Gregorian: *useGregorian, Year: y, Month: m, Highlight: highlight, Holidays: holidays, Layout: layout, monthOptions: opts})
+	}
+	// The summary footer follows single months only; in the year view it
+	// would be noise.
+	printSummary := func(y, m int) {
+		switch {
+		case noSummary:
+		case *useGregorian:
+			printGregorianMonthSummary(y, m, holidays)
+		default:
+			printShamsyMonthSummary(y, m, holidays)
+		}
+	}
 	var jy, jm, highlight int
 	var gy, gm, gd int
-	var holidays map[string]string
 	var err error
+	if len(args) != 1 {
+		if monthOpts, err = fitMonth(monthOpts, *strictWidth); err != nil {
+			fail(err)
+		}
+	}
 	switch len(args) {
 	case 0:
-		now := time.Now()
-		y0, m0, d0 := now.Date()
-		gy, gm, gd = y0, int(m0), d0
-		jy, jm, _ = gregorianToshamsy(gy, gm, gd)
-		holidays, err = fetchHolidays(jy)
+		// Both calendars and the highlight derive from one date, so that
+		// they cannot disagree around midnight.
+		today := currentDate()
+		jy, jm, highlight = today.Year, today.Month, today.Day
+		gy, gm, gd = toGregorian(jy, jm, highlight)
+		if *useGregorian {
+			gd, err = monthHighlight(gd, gregorianMonthDays(gy, gm), shamsy.FormatGregorian(gy, gm, 1, "MonthName YYYY"))
+			highlight = gd
+		} else {
+			highlight, err = monthHighlight(highlight, shamsy.MonthDays(jy, jm), shamsy.FormatShamsi(jy, jm, 1, "MonthName YYYY"))
+		}
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error fetching holidays: %v\n", err)
-			os.Exit(1)
+			fail(err)
 		}
+		view := monthView(false, jy, jm)
 		if *useGregorian {
-			printGregorianCalendar(gy, gm, gd, holidays)
-		} else {
-			_, _, shDay := gregorianToshamsy(gy, gm, gd)
-			highlight = shDay
-			printshamsyCalendar(jy, jm, highlight, holidays)
+			view = monthView(true, gy, gm)
 		}
+		renderCached(view, highlight, []int{jy}, func() {
+			if *useGregorian {
+				holidays, err = fetchHolidays(jy)
+			} else {
+				holidays, err = fetchMonthHolidays(jy, jm)
+			}
+			if err != nil {
+				fail(err)
+			}
+			if *useGregorian {
+				printMonth(gy, gm, gd, monthOpts)
+				printSummary(gy, gm)
+			} else {
+				printMonth(jy, jm, highlight, monthOpts)
+				printSummary(jy, jm)
+			}
+		})
 	case 1:
+		// Neighbouring days would be mistaken for the months next to them.
+		monthOpts.PadAdjacent = false
 		y, err := strconv.Atoi(args[0])
-		if err != nil || y < 1 {
-			fmt.Println("Invalid year argument.")
-			os.Exit(1)
+		if err == nil {
+			if err := checkYear(y, *useGregorian); err != nil {
+				fail(err)
+			}
+		}
+		if err != nil || !yearInRange(y, *useGregorian) {
+			fail(withCode(codeInvalidArgument, fmt.Errorf("invalid year argument %q (supported: %s)", args[0], supportedRange(*useGregorian))))
+		}
+		if *quarterGrid && *useGregorian {
+			fail(withCode(codeUsage, fmt.Errorf("--quarter-grid follows the Shamsi fiscal year and cannot be used with -g")))
 		}
 		if *useGregorian {
-			jy, _, _ = gregorianToshamsy(y, 1, 1)
+			jy, _, _ = fromGregorian(y, 1, 1)
 			holidays, err = fetchHolidays(jy)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error fetching holidays: %v\n", err)
-				os.Exit(1)
+				fail(err)
 			}
 			holidays2, _ := fetchHolidays(jy + 1)
-			for k, v := range holidays2 {
-				holidays[k] = v
-			}
-			for row := 0; row < 3; row++ {
-				var monthLines [4][]string
-				maxLines := 0
-				for col := 0; col < 4; col++ {
-					m := row*4 + col + 1
-					origStdout := os.Stdout
-					r, w, _ := os.Pipe()
-					os.Stdout = w
-					printGregorianCalendar(y, m, 0, holidays)
-					w.Close()
-					os.Stdout = origStdout
-					buf := make([]byte, 4096)
-					n, _ := r.Read(buf)
-					lines := strings.Split(string(buf[:n]), "\n")
-					for len(lines) > 0 && lines[len(lines)-1] == "" {
-						lines = lines[:len(lines)-1]
-					}
-					for i, line := range lines {
-						if i == 0 {
-							continue
-						}
-						visibleLine := stripAnsiCodes(line)
-						visibleLine = strings.TrimSpace(visibleLine)
-						visibleLen := len(visibleLine)
-						if visibleLen == 0 {
-							lines[i] = strings.Repeat(" ", maxTitleWidth)
-						} else if len(stripAnsiCodes(line)) < maxTitleWidth {
-							rightPad := maxTitleWidth - len(stripAnsiCodes(line))
-							lines[i] = line + strings.Repeat(" ", rightPad)
-						}
-					}
-					monthLines[col] = lines
-					if len(lines) > maxLines {
-						maxLines = len(lines)
-					}
-				}
-				for col := 0; col < 4; col++ {
-					for len(monthLines[col]) < maxLines {
-						monthLines[col] = append(monthLines[col], strings.Repeat(" ", maxTitleWidth))
-					}
-				}
-				for i := 0; i < maxLines; i++ {
-					for col := 0; col < 4; col++ {
-						fmt.Print(monthLines[col][i])
-						fmt.Print("    ")
-					}
-					fmt.Println()
-				}
-				fmt.Println()
-			}
+			holidays = shamsy.Merge(holidays, holidays2)
 		} else {
 			holidays, err = fetchHolidays(y)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error fetching holidays: %v\n", err)
-				os.Exit(1)
+				fail(err)
 			}
-			for row := 0; row < 3; row++ {
-				var monthLines [4][]string
-				maxLines := 0
-				for col := 0; col < 4; col++ {
-					m := row*4 + col + 1
-					origStdout := os.Stdout
-					r, w, _ := os.Pipe()
-					os.Stdout = w
-					printshamsyCalendar(y, m, 0, holidays)
-					w.Close()
-					os.Stdout = origStdout
-					buf := make([]byte, 4096)
-					n, _ := r.Read(buf)
-					lines := strings.Split(string(buf[:n]), "\n")
-					for len(lines) > 0 && lines[len(lines)-1] == "" {
-						lines = lines[:len(lines)-1]
-					}
-					for i, line := range lines {
-						if i == 0 {
-							continue
-						}
-						visibleLine := stripAnsiCodes(line)
-						visibleLine = strings.TrimSpace(visibleLine)
-						visibleLen := len(visibleLine)
-						if visibleLen == 0 {
-							lines[i] = strings.Repeat(" ", maxTitleWidth)
-						} else if len(stripAnsiCodes(line)) < maxTitleWidth {
-							rightPad := maxTitleWidth - len(stripAnsiCodes(line))
-							lines[i] = line + strings.Repeat(" ", rightPad)
-						}
-					}
-					monthLines[col] = lines
-					if len(lines) > maxLines {
-						maxLines = len(lines)
-					}
-				}
-				for col := 0; col < 4; col++ {
-					for len(monthLines[col]) < maxLines {
-						monthLines[col] = append(monthLines[col], strings.Repeat(" ", maxTitleWidth))
-					}
-				}
-				for i := 0; i < maxLines; i++ {
-					for col := 0; col < 4; col++ {
-						fmt.Print(monthLines[col][i])
-						fmt.Print("    ")
-					}
+		}
+		if *quarterGrid {
+			quarterOpts, err := fitQuarterGrid(monthOpts, *strictWidth)
+			if err != nil {
+				fail(err)
+			}
+			printQuarterGrid(quarterOpts, func(m int, opts monthOptions) {
+				printMonth(y, m, noHighlight, opts)
+			})
+			return
+		}
+		if plainFormat {
+			for m := 1; m <= 12; m++ {
+				if m > 1 {
 					fmt.Println()
 				}
-				fmt.Println()
+				printMonth(y, m, noHighlight, monthOpts)
 			}
+			return
+		}
+		cols, yearOpts, err := fitYear(monthOpts, *strictWidth)
+		if err != nil {
+			fail(err)
 		}
-	case 2, 3:
+		printYear(cols, yearOpts, func(m int, opts monthOptions) {
+			printMonth(y, m, noHighlight, opts)
+		})
+	case 2:
 		y, err1 := strconv.Atoi(args[0])
 		m, err2 := strconv.Atoi(args[1])
-		showHolidays := false
-		if len(args) == 3 && args[2] == "--show-holidays" {
-			showHolidays = true
+		if err1 == nil {
+			if err := checkYear(y, *useGregorian); err != nil {
+				fail(err)
+			}
 		}
-		if err1 != nil || err2 != nil || y < 1 || m < 1 || m > 12 {
-			fmt.Println("Invalid year or month argument.")
-			os.Exit(1)
+		if err1 != nil || !yearInRange(y, *useGregorian) {
+			fail(withCode(codeInvalidArgument, fmt.Errorf("invalid year argument %q (supported: %s)", args[0], supportedRange(*useGregorian))))
 		}
-		if *useGregorian {
-			jy, _, _ = gregorianToshamsy(y, 1, 1)
-			holidays, err = fetchHolidays(jy)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error fetching holidays: %v\n", err)
-				os.Exit(1)
+		if err2 != nil || m < 1 || m > 12 {
+			fail(withCode(codeInvalidArgument, fmt.Errorf("invalid month argument %q: month must be between 1 and 12 (or \"all\" for the whole year)", args[1])))
+		}
+		if *showHolidays && jsonOutput {
+			var entries []holidayJSON
+			if *useGregorian {
+				jy, _, _ = fromGregorian(y, 1, 1)
+				if holidays, err = fetchHolidays(jy); err != nil {
+					fail(err)
+				}
+				holidays2, _ := fetchHolidays(jy + 1)
+				entries = gregorianMonthHolidaysJSON(y, m, shamsy.Merge(holidays, holidays2))
+			} else {
+				if holidays, err = fetchMonthHolidays(y, m); err != nil {
+					fail(err)
+				}
+				entries = shamsyMonthHolidaysJSON(y, m, holidays)
 			}
-			holidays2, _ := fetchHolidays(jy + 1)
-			for k, v := range holidays2 {
-				holidays[k] = v
+			if err := printHolidaysJSON(entries); err != nil {
+				fail(err)
 			}
-			printGregorianCalendar(y, m, 0, holidays)
-			if showHolidays {
-				printGregorianHolidaysOfMonth(y, m, holidays)
+			return
+		}
+		if *useGregorian {
+			jy, _, _ = fromGregorian(y, 1, 1)
+			highlight, err = monthHighlight(noHighlight, gregorianMonthDays(y, m), shamsy.FormatGregorian(y, m, 1, "MonthName YYYY"))
+			if err != nil {
+				fail(err)
 			}
+			renderCached(monthView(true, y, m), highlight, []int{jy, jy + 1}, func() {
+				holidays, err = fetchHolidays(jy)
+				if err != nil {
+					fail(err)
+				}
+				holidays2, _ := fetchHolidays(jy + 1)
+				holidays = shamsy.Merge(holidays, holidays2)
+				printMonth(y, m, highlight, monthOpts)
+				printSummary(y, m)
+				if *showHolidays {
+					printGregorianHolidaysOfMonth(y, m, holidays)
+				}
+			})
 		} else {
-			holidays, err = fetchHolidays(y)
+			highlight, err = monthHighlight(noHighlight, shamsy.MonthDays(y, m), shamsy.FormatShamsi(y, m, 1, "MonthName YYYY"))
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error fetching holidays: %v\n", err)
-				os.Exit(1)
-			}
-			printshamsyCalendar(y, m, 0, holidays)
-			if showHolidays {
-				printHolidaysOfMonth(y, m, holidays)
+				fail(err)
 			}
+			renderCached(monthView(false, y, m), highlight, []int{y}, func() {
+				holidays, err = fetchMonthHolidays(y, m)
+				if err != nil {
+					fail(err)
+				}
+				printMonth(y, m, highlight, monthOpts)
+				printSummary(y, m)
+				if *showHolidays {
+					printHolidaysOfMonth(y, m, holidays)
+				}
+			})
 		}
 	default:
-		fmt.Println("Usage: shamsy-calendar [flags] [year] [month] [--show-holidays]")
+		abortOutput()
+		fmt.Println("Usage: shamsy-calendar [flags] [year] [month] [flags]")
 		fmt.Println("Try 'shamsy-calendar --help' for more information.")
 		os.Exit(1)
 	}
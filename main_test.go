@@ -0,0 +1,247 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+func TestParseCommandLine(t *testing.T) {
+	tests := []struct {
+		args       string
+		positional []string
+		show, g    bool
+		date       string
+		wantErr    bool
+	}{
+		{args: "1404 7", positional: []string{"1404", "7"}},
+		{args: "--show-holidays 1404 7", positional: []string{"1404", "7"}, show: true},
+		{args: "1404 --show-holidays 7", positional: []string{"1404", "7"}, show: true},
+		{args: "1404 7 --show-holidays", positional: []string{"1404", "7"}, show: true},
+		{args: "1404 -g 7 --show-holidays", positional: []string{"1404", "7"}, show: true, g: true},
+		{args: "-c 1404/01/01 -g", positional: nil, g: true, date: "1404/01/01"},
+		{args: "1404 --convert 1404/01/01", positional: []string{"1404"}, date: "1404/01/01"},
+		{args: "1404 --bogus 7", wantErr: true},
+		{args: "1404 7 --show-holidays=maybe", wantErr: true},
+		// Subcommands parse their own flags.
+		{args: "fiscal 1404 --date 1404/05/10", positional: []string{"fiscal", "1404", "--date", "1404/05/10"}},
+		{args: "-g fiscal --date 1404/05/10", positional: []string{"fiscal", "--date", "1404/05/10"}, g: true},
+		{args: "1404 fiscal --show-holidays", positional: []string{"1404", "fiscal"}, show: true},
+	}
+	for _, tt := range tests {
+		fs := flag.NewFlagSet("scal", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		show := fs.Bool("show-holidays", false, "")
+		g := fs.Bool("g", false, "")
+		date := fs.String("convert", "", "")
+		fs.StringVar(date, "c", "", "")
+		positional, err := parseCommandLine(fs, strings.Fields(tt.args), func(name string) bool { return name == "fiscal" })
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected an error, got %q", tt.args, positional)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", tt.args, err)
+			continue
+		}
+		if !reflect.DeepEqual(positional, tt.positional) || *show != tt.show || *g != tt.g || *date != tt.date {
+			t.Errorf("%q: got %q show=%v g=%v date=%q, want %q show=%v g=%v date=%q",
+				tt.args, positional, *show, *g, *date, tt.positional, tt.show, tt.g, tt.date)
+		}
+	}
+}
+
+// renderText returns what fn prints to stdout, without colors.
+func renderText(fn func()) string {
+	saved := noColor
+	noColor = true
+	defer func() { noColor = saved }()
+	return captureStdout(fn)
+}
+
+// recordingStatus is a statusReporter that records what it is told.
+type recordingStatus struct {
+	started, warnings []string
+}
+
+func (r *recordingStatus) Start(msg string) { r.started = append(r.started, msg) }
+func (r *recordingStatus) Done()            {}
+func (r *recordingStatus) Warn(msg string)  { r.warnings = append(r.warnings, msg) }
+
+// recordStatus replaces status with a recordingStatus for the test.
+func recordStatus(t *testing.T) *recordingStatus {
+	rec := &recordingStatus{}
+	saved := status
+	status = rec
+	t.Cleanup(func() { status = saved })
+	return rec
+}
+
+// ansiLine is a colored grid line as the year view strips it.
+var ansiLine = strings.Repeat(rgb(offday, " 12")+rgb(blue, " 13"), 7)
+
+func BenchmarkStripAnsiCodes(b *testing.B) {
+	for b.Loop() {
+		stripAnsiCodes(ansiLine)
+	}
+}
+
+// BenchmarkStripAnsiCodesCompileEachCall is stripAnsiCodes as it was before
+// the regexp became a package variable, for comparison.
+func BenchmarkStripAnsiCodesCompileEachCall(b *testing.B) {
+	for b.Loop() {
+		regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]`).ReplaceAllString(ansiLine, "")
+	}
+}
+
+func TestCaptureStdoutLarge(t *testing.T) {
+	// Far more than a single 4 KiB read or the pipe's buffer.
+	want := strings.Repeat(rgb(offday, "1404/07/10 holiday")+"\n", 10000)
+	if got := captureStdout(func() { fmt.Print(want) }); got != want {
+		t.Errorf("captured %d bytes, want %d", len(got), len(want))
+	}
+}
+
+func TestPrintMonthColumnsLarge(t *testing.T) {
+	// A month whose rendering exceeds 4 KiB must keep all its lines, or the
+	// rows of the year view misalign.
+	const lines = 300
+	big := func(opts monthOptions) {
+		for i := 0; i < lines; i++ {
+			fmt.Println(rgb(offday, fmt.Sprintf("%4d", i)))
+		}
+	}
+	small := func(opts monthOptions) { fmt.Println("small") }
+	out := stripAnsiCodes(captureStdout(func() { printMonthColumns([]func(monthOptions){small, big}, monthOptions{}) }))
+	got := strings.Split(strings.TrimSuffix(out, "\n\n"), "\n")
+	if len(got) != lines {
+		t.Fatalf("got %d rows, want %d", len(got), lines)
+	}
+	if last := strings.TrimSpace(got[lines-1]); last != fmt.Sprintf("%d", lines-1) {
+		t.Errorf("last row = %q, want %d", last, lines-1)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		in      string
+		y, m, d int
+		wantErr bool
+	}{
+		{"1403/09/05", 1403, 9, 5, false},
+		{"1403-9-5", 1403, 9, 5, false},
+		{"1403.09.05", 1403, 9, 5, false},
+		{"1403 9 5", 1403, 9, 5, false},
+		{" 1403/09/05 ", 1403, 9, 5, false},
+		{"1403 - 9 - 5", 1403, 9, 5, false},
+		{"1403 / 9 / 5", 1403, 9, 5, false},
+		{"1403\t9\t5", 1403, 9, 5, false},
+		{"1403/9", 0, 0, 0, true},
+		{"1403/09/05/01", 0, 0, 0, true},
+		{"1403//09/05", 0, 0, 0, true},
+		{"1403/ab/05", 0, 0, 0, true},
+		{"14o3/09/05", 0, 0, 0, true},
+		{"1403/13/05", 0, 0, 0, true},
+		{"1403/09/32", 0, 0, 0, true},
+		{"1403/00/05", 0, 0, 0, true},
+		{"0/09/05", 0, 0, 0, true},
+		{"", 0, 0, 0, true},
+	}
+	for _, tt := range tests {
+		y, m, d, err := parseDate(tt.in)
+		if (err != nil) != tt.wantErr || y != tt.y || m != tt.m || d != tt.d {
+			t.Errorf("parseDate(%q) = %d, %d, %d, %v", tt.in, y, m, d, err)
+			continue
+		}
+		if err != nil && errorCode(err) != codeInvalidDate {
+			t.Errorf("parseDate(%q): error code %s, want %s", tt.in, errorCode(err), codeInvalidDate)
+		}
+	}
+}
+
+func TestParseCalendarDate(t *testing.T) {
+	tests := []struct {
+		in         string
+		gregorian  bool
+		gy, gm, gd int
+		date       shamsy.Date
+		wantErr    string
+	}{
+		{in: "1404 7 10", gy: 2025, gm: 10, gd: 2, date: shamsy.Date{Year: 1404, Month: 7, Day: 10}},
+		{in: "2025 - 10 - 2", gregorian: true, gy: 2025, gm: 10, gd: 2, date: shamsy.Date{Year: 1404, Month: 7, Day: 10}},
+		{in: "1403/12/30", gy: 2025, gm: 3, gd: 20, date: shamsy.Date{Year: 1403, Month: 12, Day: 30}},
+		{in: "1404/12/30", wantErr: "Esfand 1404 has 29 days"},
+		{in: "1404/07/31", wantErr: "Mehr 1404 has 30 days"},
+		{in: "2025/02/29", gregorian: true, wantErr: "February 2025 has 28 days"},
+		{in: "5000/01/01", wantErr: "outside the supported range"},
+	}
+	for _, tt := range tests {
+		gy, gm, gd, date, err := parseCalendarDate(tt.in, tt.gregorian)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseCalendarDate(%q, %v) = %v, want an error containing %q", tt.in, tt.gregorian, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || gy != tt.gy || gm != tt.gm || gd != tt.gd || date != tt.date {
+			t.Errorf("parseCalendarDate(%q, %v) = %d-%d-%d %v, %v", tt.in, tt.gregorian, gy, gm, gd, date, err)
+		}
+	}
+}
+
+// discardStdout sends stdout to the null device for the rest of the test.
+func discardStdout(tb testing.TB) {
+	f, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	saved := os.Stdout
+	os.Stdout = f
+	tb.Cleanup(func() {
+		os.Stdout = saved
+		f.Close()
+	})
+}
+
+func BenchmarkPrintYear(b *testing.B) {
+	discardStdout(b)
+	holidays := shamsy.Merge()
+	for b.Loop() {
+		printYear(3, monthOptions{}, func(m int, opts monthOptions) {
+			printshamsyCalendar(1404, m, noHighlight, holidays, opts)
+		})
+	}
+}
+
+func TestPrintYearAlignment(t *testing.T) {
+	opts := monthOptions{}
+	for _, cols := range []int{1, 2, 3, 4, 6} {
+		out := renderText(func() {
+			printYear(cols, opts, func(m int, opts monthOptions) {
+				printshamsyCalendar(1404, m, noHighlight, nil, opts)
+			})
+		})
+		titles := 0
+		for _, line := range strings.Split(out, "\n") {
+			if line == "" {
+				continue
+			}
+			if w := visibleWidth(line); w != yearWidth(cols, opts) {
+				t.Errorf("%d columns: line %q is %d wide, want %d", cols, line, w, yearWidth(cols, opts))
+			}
+			titles += strings.Count(line, " 1404=")
+		}
+		if titles != 12 {
+			t.Errorf("%d columns: %d month titles, want 12", cols, titles)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// holidayMarker is printed after the number of every holiday with --marker,
+// so that holidays stand out without colors. markerWidth is its width in
+// terminal columns (0 without a marker).
+var (
+	holidayMarker string
+	markerWidth   int
+)
+
+// parseMarker validates a --marker glyph: a single character that is one or
+// two columns wide, such as "*", "•" or "❌".
+func parseMarker(s string) (string, int, error) {
+	width := uniseg.StringWidth(s)
+	if uniseg.GraphemeClusterCount(s) != 1 || width < 1 || width > 2 {
+		return "", 0, withCode(codeInvalidArgument, fmt.Errorf("invalid --marker %q: must be a single character", s))
+	}
+	return s, width, nil
+}
+
+// dayCell formats day d in a cell of width cw, followed by the holiday
+// marker when marked. Without a marker the day is simply right-aligned.
+func dayCell(d, cw int, marked bool) string {
+	if markerWidth == 0 {
+		return fmt.Sprintf("%*d", cw, d)
+	}
+	if marked {
+		return fmt.Sprintf("%*d", cw-markerWidth, d) + holidayMarker
+	}
+	return fmt.Sprintf("%*d", cw-markerWidth, d) + strings.Repeat(" ", markerWidth)
+}
+
+// labelCell formats a header label so that it lines up with the day numbers
+// of dayCell.
+func labelCell(label string, cw int) string {
+	return fmt.Sprintf("%*s", cw-markerWidth, label) + strings.Repeat(" ", markerWidth)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// markColor colors the days given with --mark.
+var markColor = Color{255, 105, 180}
+
+// marks holds the days given with --mark as Shamsi dates, so that a mark
+// applies in either calendar's view.
+var marks = map[shamsy.Date]bool{}
+
+// parseMarks parses the comma-separated --mark list. Each item is a Shamsi
+// date, optionally prefixed with "s:", or a Gregorian date prefixed with
+// "g:", e.g. "1404/07/12,g:2025-10-04". Marks outside the rendered month are
+// simply not shown.
+func parseMarks(list string) error {
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		isGregorian := false
+		switch prefix, rest, found := strings.Cut(item, ":"); {
+		case found && strings.EqualFold(prefix, "g"):
+			isGregorian, item = true, rest
+		case found && strings.EqualFold(prefix, "s"):
+			item = rest
+		}
+		_, _, _, date, err := parseCalendarDate(item, isGregorian)
+		if err != nil {
+			return err
+		}
+		marks[date] = true
+	}
+	return nil
+}
+
+// isMarked reports whether a Shamsi date was given with --mark.
+func isMarked(date shamsy.Date) bool {
+	return marks[date]
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// setMarks empties marks for the test.
+func setMarks(t *testing.T) {
+	saved := marks
+	marks = map[shamsy.Date]bool{}
+	t.Cleanup(func() { marks = saved })
+}
+
+func TestParseMarks(t *testing.T) {
+	tests := []struct {
+		list    string
+		want    []shamsy.Date
+		wantErr bool
+	}{
+		{list: "1404/07/12", want: []shamsy.Date{{Year: 1404, Month: 7, Day: 12}}},
+		{list: "s:1404/07/12", want: []shamsy.Date{{Year: 1404, Month: 7, Day: 12}}},
+		{list: "S:1404-7-12", want: []shamsy.Date{{Year: 1404, Month: 7, Day: 12}}},
+		// Gregorian dates landing on the first or last day of a Shamsi month.
+		{list: "g:2025-10-23", want: []shamsy.Date{{Year: 1404, Month: 8, Day: 1}}},
+		{list: "G:2025/10/22", want: []shamsy.Date{{Year: 1404, Month: 7, Day: 30}}},
+		{list: "g:2025-03-20", want: []shamsy.Date{{Year: 1403, Month: 12, Day: 30}}},
+		{list: "g:2025-03-21", want: []shamsy.Date{{Year: 1404, Month: 1, Day: 1}}},
+		{list: "1404/07/30, g:2025-10-23", want: []shamsy.Date{{Year: 1404, Month: 7, Day: 30}, {Year: 1404, Month: 8, Day: 1}}},
+		{list: " , ,"},
+		{list: "g:2025-02-29", wantErr: true},
+		{list: "1404/12/30", wantErr: true},
+		{list: "x:1404/07/12", wantErr: true},
+	}
+	for _, tt := range tests {
+		setMarks(t)
+		err := parseMarks(tt.list)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseMarks(%q) = %v", tt.list, err)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		want := map[shamsy.Date]bool{}
+		for _, d := range tt.want {
+			want[d] = true
+		}
+		if !reflect.DeepEqual(marks, want) {
+			t.Errorf("parseMarks(%q) marked %v, want %v", tt.list, marks, want)
+		}
+	}
+}
+
+func TestMarksAtMonthBoundaries(t *testing.T) {
+	setMarks(t)
+	// 1404/07/30 and 1404/08/01 end and start two months, and 2025-11-01
+	// fills a padded cell of October.
+	if err := parseMarks("g:2025-10-23,1404/07/30,g:2025-11-01"); err != nil {
+		t.Fatal(err)
+	}
+	savedDepth, savedNoColor := colorDepth, noColor
+	colorDepth, noColor = depthTrueColor, false
+	t.Cleanup(func() { colorDepth, noColor = savedDepth, savedNoColor })
+	marked := "\x1b[" + colorCode(markColor) + "m"
+
+	opts := monthOptions{PadAdjacent: true}
+	tests := []struct {
+		name   string
+		render func()
+		want   int
+	}{
+		// The marks in the padded cells of the neighbouring month are not
+		// colored.
+		{"Aban 1404", func() { printshamsyCalendar(1404, 8, noHighlight, nil, opts) }, 2},
+		{"Mehr 1404", func() { printshamsyCalendar(1404, 7, noHighlight, nil, opts) }, 1},
+		{"October 2025", func() { printGregorianCalendar(2025, 10, noHighlight, nil, opts) }, 2},
+		{"November 2025", func() { printGregorianCalendar(2025, 11, noHighlight, nil, opts) }, 1},
+	}
+	for _, tt := range tests {
+		if got := strings.Count(captureStdout(tt.render), marked); got != tt.want {
+			t.Errorf("%s: %d marked days, want %d", tt.name, got, tt.want)
+		}
+	}
+	if c := shamsyDayColor(1404, 7, 29, noHighlight, nil); c == markColor {
+		t.Error("1404/07/29 is marked")
+	}
+	if c := gregorianDayColor(2025, 10, 23, noHighlight, nil); c != markColor {
+		t.Errorf("2025-10-23 is colored %v, want the mark color", c)
+	}
+}
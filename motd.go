@@ -0,0 +1,106 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// defaultMotdWidth is the width motd wraps its summary to without --width.
+const defaultMotdWidth = 80
+
+// handleMotd implements "motd [--width N] [--color always|never]": the mini
+// month with today highlighted and today's summary line, for /etc/motd and
+// login scripts. Holidays are only read from the cache, so motd never waits
+// for the network, and the rendered text is cached for the day: it is built
+// again when the date, the cached holidays, the config or the flags change.
+// Colors are left out unless --color always. With isGregorian the month is
+// the Gregorian one.
+func handleMotd(args []string, isGregorian bool) error {
+	fs := flag.NewFlagSet("motd", flag.ContinueOnError)
+	width := fs.Int("width", defaultMotdWidth, "Wrap the summary to this many columns")
+	color := fs.String("color", "never", "Colors: always or never")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return withCode(codeUsage, fmt.Errorf("usage: shamsy-calendar motd [--width N] [--color always|never]"))
+	}
+	opts := monthOptions{Mini: true}
+	if *width < opts.monthWidth() {
+		return withCode(codeInvalidArgument, fmt.Errorf("invalid --width %d: the mini month needs at least %d columns", *width, opts.monthWidth()))
+	}
+	switch *color {
+	case "always":
+		noColor = false
+	case "never":
+		noColor = true
+	default:
+		return withCode(codeInvalidArgument, fmt.Errorf("invalid --color %q (want always or never)", *color))
+	}
+
+	holidayOptions.Offline = true
+	holidayOptions.Progress = nil
+	renderCacheEnabled = true
+	today := currentDate()
+	key := []string{"motd", today.String(), strconv.Itoa(*width), *color}
+	years := []int{today.Year}
+	gy, gm, gd := currentTime().Date()
+	// A Gregorian month can reach into the neighbouring Shamsi year.
+	first := dateFromGregorian(gy, int(gm), 1).Year
+	last := dateFromGregorian(gy, int(gm), gregorianMonthDays(gy, int(gm))).Year
+	if isGregorian {
+		years = []int{first}
+		if last != first {
+			years = append(years, last)
+		}
+	}
+	renderCached(key, today.Day, years, func() {
+		holidays, err := fetchMonthHolidays(today.Year, today.Month)
+		r := renderOptions{Year: today.Year, Month: today.Month, Highlight: today.Day, monthOptions: opts}
+		if isGregorian {
+			holidays, err = fetchHolidays(first)
+			if last != first {
+				next, _ := fetchHolidays(last)
+				holidays = shamsy.Merge(holidays, next)
+			}
+			r = renderOptions{Gregorian: true, Year: gy, Month: int(gm), Highlight: gd, monthOptions: opts}
+		}
+		r.Holidays = holidays
+		renderMonth(r)
+		summary := captureStdout(func() { printTodayLine(isGregorian) })
+		for _, line := range wrapText(strings.TrimSuffix(summary, "\n"), *width) {
+			fmt.Println(line)
+		}
+		if err != nil {
+			fmt.Println(rgb(yellow, "(holidays not cached yet; run scal once while online)"))
+		}
+	})
+	return nil
+}
+
+// wrapText breaks s into lines of at most width visible columns at spaces.
+// A word wider than width gets a line of its own.
+func wrapText(s string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		switch {
+		case line == "":
+			line = word
+		case visibleWidth(line)+1+visibleWidth(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// ncalPosition returns where day d of a month whose 1st falls on weekday
+// column first is placed in the transposed layout: the weekday row (0-6) and
+// the week column (0-5).
+func ncalPosition(first, d int) (row, col int) {
+	pos := first + d - 1
+	return pos % 7, pos / 7
+}
+
+// printNcal prints a transposed month: one row per weekday labelled with
+// labels (unless opts.NoWeekdayHeader), one column per week. dayColor gives the color of each day.
+func printNcal(titleText string, labels []string, first, days int, dayColor func(d int) Color, holiday func(d int) bool, opts monthOptions) {
+	if !opts.NoHeader {
+		fmt.Println(rgb(red, monthTitle(titleText, opts)))
+	}
+	printMonthLength(days, opts)
+	cw := opts.cellWidth()
+	cols := weekRows(first, days)
+	var grid [7][6]int
+	for d := 1; d <= days; d++ {
+		row, col := ncalPosition(first, d)
+		grid[row][col] = d
+	}
+	for row := 0; row < 7; row++ {
+		if !opts.NoWeekdayHeader {
+			fmt.Print(rgb(green, fmt.Sprintf("%-2s", labels[row])))
+		}
+		for col := 0; col < cols; col++ {
+			d := grid[row][col]
+			if d == 0 {
+				fmt.Print(strings.Repeat(" ", cw))
+				continue
+			}
+			fmt.Print(rgb(dayColor(d), dayCell(d, cw, holiday(d))))
+		}
+		fmt.Println()
+	}
+	if !opts.NoTrailingNewline {
+		fmt.Print("\n")
+	}
+}
+
+func printshamsyNcal(jy, jm, highlight int, holidays *shamsy.HolidayCalendar, opts monthOptions) {
+	checkHighlight(highlight, shamsy.MonthDays(jy, jm))
+	printNcal(shamsyMonthTitle(jy, jm), shamsyWeekHeader(),
+		getFirstWeekday(jy, jm), shamsy.MonthDays(jy, jm),
+		func(d int) Color { return shamsyDayColor(jy, jm, d, highlight, holidays) },
+		func(d int) bool {
+			_, ok := holidays.IsHoliday(shamsy.Date{Year: jy, Month: jm, Day: d})
+			return ok
+		}, opts)
+}
+
+func printGregorianNcal(year, month, highlight int, shamsyHolidays *shamsy.HolidayCalendar, opts monthOptions) {
+	checkHighlight(highlight, gregorianMonthDays(year, month))
+	printNcal(gregorianMonthTitle(year, month), gregorianWeekHeader(),
+		getGregorianFirstWeekday(year, month), gregorianMonthDays(year, month),
+		func(d int) Color { return gregorianDayColor(year, month, d, highlight, shamsyHolidays) },
+		func(d int) bool {
+			_, ok := shamsyHolidays.IsGregorianHoliday(year, month, d)
+			return ok
+		}, opts)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNcalPosition(t *testing.T) {
+	tests := []struct {
+		first, d, row, col int
+	}{
+		{0, 1, 0, 0},
+		{0, 7, 6, 0},
+		{0, 8, 0, 1},
+		{6, 1, 6, 0},
+		{6, 2, 0, 1},
+		{6, 31, 1, 5},
+		{3, 29, 3, 4},
+	}
+	for _, tt := range tests {
+		if row, col := ncalPosition(tt.first, tt.d); row != tt.row || col != tt.col {
+			t.Errorf("ncalPosition(%d, %d) = %d, %d, want %d, %d", tt.first, tt.d, row, col, tt.row, tt.col)
+		}
+	}
+}
+
+func TestNcalGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		render func()
+		want   []string
+	}{
+		{
+			// The 1st falls on a Friday, so the month needs six columns.
+			name:   "Farvardin 1404",
+			render: func() { printshamsyNcal(1404, 1, noHighlight, nil, monthOptions{}) },
+			want: []string{
+				"========Farvardin 1404========",
+				"Sh       2   9  16  23  30",
+				"Ye       3  10  17  24  31",
+				"Do       4  11  18  25    ",
+				"Se       5  12  19  26    ",
+				"Ch       6  13  20  27    ",
+				"Pa       7  14  21  28    ",
+				"Jo   1   8  15  22  29    ",
+			},
+		},
+		{
+			name:   "Shahrivar 1404",
+			render: func() { printshamsyNcal(1404, 6, noHighlight, nil, monthOptions{}) },
+			want: []string{
+				"========Shahrivar 1404========",
+				"Sh   1   8  15  22  29",
+				"Ye   2   9  16  23  30",
+				"Do   3  10  17  24  31",
+				"Se   4  11  18  25    ",
+				"Ch   5  12  19  26    ",
+				"Pa   6  13  20  27    ",
+				"Jo   7  14  21  28    ",
+			},
+		},
+		{
+			name:   "July 2025",
+			render: func() { printGregorianNcal(2025, 7, noHighlight, nil, monthOptions{}) },
+			want: []string{
+				"==========July 2025===========",
+				"Su       6  13  20  27",
+				"Mo       7  14  21  28",
+				"Tu   1   8  15  22  29",
+				"We   2   9  16  23  30",
+				"Th   3  10  17  24  31",
+				"Fr   4  11  18  25    ",
+				"Sa   5  12  19  26    ",
+			},
+		},
+		{
+			name: "no weekday labels",
+			render: func() {
+				printshamsyNcal(1404, 6, noHighlight, nil, monthOptions{NoWeekdayHeader: true, NoHeader: true})
+			},
+			want: []string{
+				"   1   8  15  22  29",
+				"   2   9  16  23  30",
+				"   3  10  17  24  31",
+				"   4  11  18  25    ",
+				"   5  12  19  26    ",
+				"   6  13  20  27    ",
+				"   7  14  21  28    ",
+			},
+		},
+	}
+	for _, tt := range tests {
+		want := strings.Join(tt.want, "\n") + "\n\n"
+		if got := renderText(tt.render); got != want {
+			t.Errorf("%s:\ngot:\n%s\nwant:\n%s", tt.name, got, want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import "strings"
+
+// gregorianObservance is a fixed-date Gregorian observance shown with
+// --gregorian-events. Observances are informational only: the Iranian
+// holidays stay the authoritative off-days.
+type gregorianObservance struct {
+	Month   int
+	Day     int
+	Name    string
+	Country string // ISO 3166-1 alpha-2 code, or "intl" for international days
+}
+
+// gregorianObservances is the embedded observance table. Add new rows here;
+// the Country tag lets the table grow beyond international days.
+var gregorianObservances = []gregorianObservance{
+	{1, 1, "New Year's Day", "intl"},
+	{2, 14, "Valentine's Day", "intl"},
+	{3, 8, "International Women's Day", "intl"},
+	{4, 22, "Earth Day", "intl"},
+	{5, 1, "International Workers' Day", "intl"},
+	{6, 5, "World Environment Day", "intl"},
+	{10, 24, "United Nations Day", "intl"},
+	{12, 25, "Christmas Day", "intl"},
+	{12, 31, "New Year's Eve", "intl"},
+}
+
+// observanceColor marks observance days in the Gregorian grid.
+var observanceColor = Color{255, 165, 0}
+
+// gregorianEvents enables the observance overlay in the -g views.
+var gregorianEvents bool
+
+// lookupObservance returns the names of the observances on a Gregorian
+// month/day joined with "; ".
+func lookupObservance(month, day int) (string, bool) {
+	var names []string
+	for _, o := range gregorianObservances {
+		if o.Month == month && o.Day == day {
+			names = append(names, o.Name)
+		}
+	}
+	return strings.Join(names, "; "), len(names) > 0
+}
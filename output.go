@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Settings of -o/--output.
+var (
+	outputPath   string // file the primary output goes to; stdout when empty
+	outputForce  bool   // replace an existing file
+	outputMkdir  bool   // create missing parent directories
+	outputFile   *os.File
+	outputStdout *os.File // the real stdout while the output is redirected
+)
+
+// startOutput redirects stdout to a temporary file next to outputPath, which
+// finishOutput later renames into place, so the named file is either written
+// completely or not at all. Colors are turned off since the output is not a
+// terminal. It does nothing without -o or when the output is already
+// redirected.
+func startOutput() error {
+	if outputPath == "" || outputFile != nil {
+		return nil
+	}
+	if _, err := os.Stat(outputPath); err == nil && !outputForce {
+		return withCode(codeUsage, fmt.Errorf("%s already exists (use --force to overwrite it)", outputPath))
+	}
+	dir := filepath.Dir(outputPath)
+	if outputMkdir {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %v", err)
+		}
+	}
+	f, err := os.CreateTemp(dir, "."+filepath.Base(outputPath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %v", err)
+	}
+	// CreateTemp makes the file private; give it the usual permissions.
+	f.Chmod(0644)
+	outputFile, outputStdout = f, os.Stdout
+	os.Stdout = f
+	noColor = true
+	return nil
+}
+
+// finishOutput moves the completed output to outputPath. Without --force it
+// refuses to replace a file that appeared in the meantime.
+func finishOutput() error {
+	if outputFile == nil {
+		return nil
+	}
+	f, tmp := outputFile, outputFile.Name()
+	os.Stdout, outputFile = outputStdout, nil
+	err := f.Sync()
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		if outputForce {
+			err = os.Rename(tmp, outputPath)
+		} else if err = os.Link(tmp, outputPath); err == nil {
+			os.Remove(tmp)
+		}
+	}
+	if err != nil {
+		os.Remove(tmp)
+		if os.IsExist(err) {
+			return withCode(codeUsage, fmt.Errorf("%s already exists (use --force to overwrite it)", outputPath))
+		}
+		return fmt.Errorf("failed to write %s: %v", outputPath, err)
+	}
+	return nil
+}
+
+// abortOutput discards the partial output and restores stdout, leaving any
+// existing file at outputPath untouched. It is called on every error exit.
+func abortOutput() {
+	if outputFile == nil {
+		return
+	}
+	tmp := outputFile.Name()
+	outputFile.Close()
+	os.Remove(tmp)
+	os.Stdout, outputFile = outputStdout, nil
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setOutput configures -o for a test and restores the settings afterwards.
+func setOutput(t *testing.T, path string, force, mkdir bool) {
+	savedNoColor := noColor
+	outputPath, outputForce, outputMkdir = path, force, mkdir
+	t.Cleanup(func() {
+		abortOutput()
+		outputPath, outputForce, outputMkdir = "", false, false
+		noColor = savedNoColor
+	})
+}
+
+// readFile returns the contents of path, or "" when it does not exist.
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// leftovers returns the files in dir other than keep.
+func leftovers(t *testing.T, dir, keep string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		if e.Name() != keep {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
+
+func TestOutputWritesFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "mehr.txt")
+	setOutput(t, path, false, false)
+	if err := startOutput(); err != nil {
+		t.Fatal(err)
+	}
+	if !noColor {
+		t.Error("colors were not turned off for -o")
+	}
+	fmt.Println("Mehr 1404")
+	if got := readFile(t, path); got != "" {
+		t.Errorf("the file appeared before the output was complete: %q", got)
+	}
+	if err := finishOutput(); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, path); got != "Mehr 1404\n" {
+		t.Errorf("file = %q", got)
+	}
+	if names := leftovers(t, dir, "mehr.txt"); len(names) != 0 {
+		t.Errorf("temporary files left behind: %v", names)
+	}
+}
+
+func TestOutputOverwriteProtection(t *testing.T) {
+	tests := []struct {
+		name    string
+		force   bool
+		want    string
+		wantErr bool
+	}{
+		{"without --force", false, "old\n", true},
+		{"with --force", true, "new\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "out.txt")
+			if err := os.WriteFile(path, []byte("old\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			setOutput(t, path, tt.force, false)
+			err := startOutput()
+			if err == nil {
+				fmt.Println("new")
+				err = finishOutput()
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && errorCode(err) != codeUsage {
+				t.Errorf("error code %s, want %s", errorCode(err), codeUsage)
+			}
+			if got := readFile(t, path); got != tt.want {
+				t.Errorf("file = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutputFileAppearsMidWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.txt")
+	setOutput(t, path, false, false)
+	if err := startOutput(); err != nil {
+		t.Fatal(err)
+	}
+	fmt.Println("new")
+	if err := os.WriteFile(path, []byte("other\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := finishOutput(); err == nil {
+		t.Error("a file created while writing was replaced without --force")
+	}
+	if got := readFile(t, path); got != "other\n" {
+		t.Errorf("file = %q, want the other writer's", got)
+	}
+	if names := leftovers(t, dir, "out.txt"); len(names) != 0 {
+		t.Errorf("temporary files left behind: %v", names)
+	}
+}
+
+func TestOutputAbortKeepsExistingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.txt")
+	if err := os.WriteFile(path, []byte("old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	setOutput(t, path, true, false)
+	if err := startOutput(); err != nil {
+		t.Fatal(err)
+	}
+	fmt.Println("partial")
+	// An error exit in the middle of the output.
+	abortOutput()
+	if got := readFile(t, path); got != "old\n" {
+		t.Errorf("file = %q, want it untouched", got)
+	}
+	if names := leftovers(t, dir, "out.txt"); len(names) != 0 {
+		t.Errorf("temporary files left behind: %v", names)
+	}
+}
+
+func TestOutputMkdir(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a", "b", "out.txt")
+	setOutput(t, path, false, false)
+	if err := startOutput(); err == nil {
+		t.Fatal("expected an error for a missing directory without --mkdir")
+	}
+	outputMkdir = true
+	if err := startOutput(); err != nil {
+		t.Fatal(err)
+	}
+	fmt.Println("ok")
+	if err := finishOutput(); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, path); got != "ok\n" {
+		t.Errorf("file = %q", got)
+	}
+}
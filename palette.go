@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// lightBackground reports whether COLORFGBG ("fg;bg", as set by rxvt,
+// Konsole, iTerm2 and others) names a light background color. Terminals
+// that do not set it are assumed to be dark.
+func lightBackground() bool {
+	fields := strings.Split(os.Getenv("COLORFGBG"), ";")
+	bg, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil {
+		return false
+	}
+	return bg == 7 || (bg >= 9 && bg <= 15)
+}
+
+// lightPalette is set once useLightPalette has swapped the colors.
+var lightPalette bool
+
+// useLightPalette swaps the colors meant for dark terminals for darker
+// variants that stay readable on a light background.
+func useLightPalette() {
+	lightPalette = true
+	offday = Color{200, 0, 0}
+	weekendColor = Color{160, 80, 80}
+	red = Color{40, 40, 40}
+	green = Color{90, 90, 90}
+	blue = Color{0, 95, 175}
+	yellow = Color{175, 95, 0}
+	cyan = Color{0, 135, 135}
+	purple = Color{135, 0, 175}
+	observanceColor = Color{160, 80, 0}
+	halfDayColor = Color{200, 90, 20}
+	adjacentColor = Color{170, 170, 170}
+	markColor = Color{190, 0, 110}
+	estimatedColor = Color{215, 95, 95}
+	weekdayTints = []Color{
+		{0, 95, 175},
+		{0, 130, 70},
+		{130, 110, 0},
+		{170, 80, 30},
+		{120, 50, 150},
+		{60, 80, 180},
+		{0, 125, 125},
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// paranoid cross-checks every displayed Shamsi↔Gregorian mapping with
+// --paranoid: the converter's result is compared with an independent
+// reference calendar and converted back, and a date failing either check is
+// marked with '!' and reported with both candidates.
+var paranoid bool
+
+// toGregorian and fromGregorian are the conversions --paranoid checks. They
+// are variables so that tests can inject a broken converter; every date scal
+// shows goes through them, so the checked mapping is the displayed one.
+var (
+	toGregorian   = shamsy.ToGregorian
+	fromGregorian = shamsy.FromGregorian
+)
+
+// dateFromGregorian is shamsy.DateFromGregorian through fromGregorian.
+func dateFromGregorian(gy, gm, gd int) shamsy.Date {
+	jy, jm, jd := fromGregorian(gy, gm, gd)
+	return shamsy.Date{Year: jy, Month: jm, Day: jd}
+}
+
+// gregorianOf is Date.Gregorian through toGregorian.
+func gregorianOf(d shamsy.Date) shamsy.DateInfo {
+	return gregorianDateInfo(d.Year, d.Month, d.Day)
+}
+
+// gregorianDateInfo is shamsy.ShamsyToGregorianDate through toGregorian.
+func gregorianDateInfo(jy, jm, jd int) shamsy.DateInfo {
+	gy, gm, gd := toGregorian(jy, jm, jd)
+	return shamsy.DateInfo{Year: gy, Month: gm, Day: gd, DayWeek: shamsy.WeekdayName(gy, gm, gd)}
+}
+
+// shamsiDateInfo is shamsy.GregorianToShamsyDate through fromGregorian.
+func shamsiDateInfo(gy, gm, gd int) shamsy.DateInfo {
+	jy, jm, jd := fromGregorian(gy, gm, gd)
+	return shamsy.DateInfo{Year: jy, Month: jm, Day: jd, DayWeek: shamsy.WeekdayName(gy, gm, gd)}
+}
+
+// checkedConversions memoizes the cross-checks, keyed by calendar and date,
+// so that a date shown several times is checked and reported once.
+var checkedConversions = map[[4]int]bool{}
+
+// mismatchWarnings holds the reports of failed cross-checks until the view is
+// printed, so that they do not break up the grid.
+var mismatchWarnings []string
+
+// reportMismatches prints the reports of failed cross-checks.
+func reportMismatches() {
+	for _, w := range mismatchWarnings {
+		status.Warn(w)
+	}
+	mismatchWarnings = nil
+}
+
+// shamsyMismatch reports whether the Shamsi date jy/jm/jd fails the
+// cross-check: its Gregorian date must match the reference and convert back
+// to it. It is always false without --paranoid.
+func shamsyMismatch(jy, jm, jd int) bool {
+	if !paranoid {
+		return false
+	}
+	key := [4]int{0, jy, jm, jd}
+	if bad, ok := checkedConversions[key]; ok {
+		return bad
+	}
+	gy, gm, gd := toGregorian(jy, jm, jd)
+	ry, rm, rd := referenceGregorian(jy, jm, jd)
+	by, bm, bd := fromGregorian(gy, gm, gd)
+	bad := gy != ry || gm != rm || gd != rd || by != jy || bm != jm || bd != jd
+	if bad {
+		mismatchWarnings = append(mismatchWarnings, fmt.Sprintf("conversion mismatch: Shamsi %s converts to %s, which converts back to %s; candidates: %s or %s (reference)",
+			shamsy.FormatShamsi(jy, jm, jd, "YYYY/MM/DD"),
+			shamsy.FormatGregorian(gy, gm, gd, "YYYY/MM/DD"),
+			shamsy.FormatShamsi(by, bm, bd, "YYYY/MM/DD"),
+			shamsy.FormatGregorian(gy, gm, gd, "YYYY/MM/DD"),
+			shamsy.FormatGregorian(ry, rm, rd, "YYYY/MM/DD")))
+	}
+	checkedConversions[key] = bad
+	return bad
+}
+
+// gregorianMismatch is shamsyMismatch for the Gregorian date gy/gm/gd.
+func gregorianMismatch(gy, gm, gd int) bool {
+	if !paranoid {
+		return false
+	}
+	key := [4]int{1, gy, gm, gd}
+	if bad, ok := checkedConversions[key]; ok {
+		return bad
+	}
+	jy, jm, jd := fromGregorian(gy, gm, gd)
+	ry, rm, rd := referenceShamsi(gy, gm, gd)
+	by, bm, bd := toGregorian(jy, jm, jd)
+	bad := jy != ry || jm != rm || jd != rd || by != gy || bm != gm || bd != gd
+	if bad {
+		mismatchWarnings = append(mismatchWarnings, fmt.Sprintf("conversion mismatch: Gregorian %s converts to %s, which converts back to %s; candidates: %s or %s (reference)",
+			shamsy.FormatGregorian(gy, gm, gd, "YYYY/MM/DD"),
+			shamsy.FormatShamsi(jy, jm, jd, "YYYY/MM/DD"),
+			shamsy.FormatGregorian(by, bm, bd, "YYYY/MM/DD"),
+			shamsy.FormatShamsi(jy, jm, jd, "YYYY/MM/DD"),
+			shamsy.FormatShamsi(ry, rm, rd, "YYYY/MM/DD")))
+	}
+	checkedConversions[key] = bad
+	return bad
+}
+
+// The reference calendar shares no code with the shamsy package: it counts
+// days from 1 Farvardin 1 with the 33-year leap cycle spelled out as a table,
+// and leaves the Gregorian calendar to the time package.
+var (
+	// referenceEpoch is 1 Farvardin 1 in the proleptic Gregorian calendar.
+	referenceEpoch = time.Date(622, time.March, 21, 0, 0, 0, 0, time.UTC)
+	// referenceLeap marks the leap years of the 33-year cycle by year % 33.
+	referenceLeap = map[int]bool{1: true, 5: true, 9: true, 13: true, 17: true, 22: true, 26: true, 30: true}
+)
+
+// referenceCycleDays is the length of a 33-year cycle with its 8 leap years.
+const referenceCycleDays = 33*365 + 8
+
+// referenceYearDays returns the length of a Shamsi year in the reference
+// calendar.
+func referenceYearDays(jy int) int {
+	if referenceLeap[jy%33] {
+		return 366
+	}
+	return 365
+}
+
+// referenceMonthDays returns the length of a Shamsi month in the reference
+// calendar.
+func referenceMonthDays(jy, jm int) int {
+	switch {
+	case jm <= 6:
+		return 31
+	case jm <= 11:
+		return 30
+	}
+	return referenceYearDays(jy) - 336
+}
+
+// referenceGregorian converts a Shamsi date with the reference calendar.
+func referenceGregorian(jy, jm, jd int) (int, int, int) {
+	// Years 1 to 33 form the first cycle, as jy % 33 repeats from there.
+	days := (jy - 1) / 33 * referenceCycleDays
+	for y := (jy-1)/33*33 + 1; y < jy; y++ {
+		days += referenceYearDays(y)
+	}
+	for m := 1; m < jm; m++ {
+		days += referenceMonthDays(jy, m)
+	}
+	t := referenceEpoch.AddDate(0, 0, days+jd-1)
+	return t.Year(), int(t.Month()), t.Day()
+}
+
+// referenceShamsi converts a Gregorian date with the reference calendar.
+func referenceShamsi(gy, gm, gd int) (int, int, int) {
+	// Durations cannot span centuries, so the days are counted in seconds.
+	days := int((time.Date(gy, time.Month(gm), gd, 0, 0, 0, 0, time.UTC).Unix() - referenceEpoch.Unix()) / 86400)
+	jy := days/referenceCycleDays*33 + 1
+	days %= referenceCycleDays
+	for days >= referenceYearDays(jy) {
+		days -= referenceYearDays(jy)
+		jy++
+	}
+	jm := 1
+	for days >= referenceMonthDays(jy, jm) {
+		days -= referenceMonthDays(jy, jm)
+		jm++
+	}
+	return jy, jm, days + 1
+}
+
+// mismatchCell marks a grid cell whose date failed the cross-check by
+// replacing its leading space with '!'.
+func mismatchCell(cell string, bad bool) string {
+	if bad && len(cell) > 0 && cell[0] == ' ' {
+		return "!" + cell[1:]
+	}
+	return cell
+}
+
+// mismatchNote is appended to a listed conversion that failed the
+// cross-check.
+func mismatchNote(bad bool) string {
+	if bad {
+		return rgb(offday, " !")
+	}
+	return ""
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// resetParanoid enables --paranoid with fresh memoization for a test and
+// restores the converters afterwards.
+func resetParanoid(t *testing.T) {
+	paranoid = true
+	checkedConversions = map[[4]int]bool{}
+	mismatchWarnings = nil
+	t.Cleanup(func() {
+		paranoid = false
+		toGregorian, fromGregorian = shamsy.ToGregorian, shamsy.FromGregorian
+		checkedConversions = map[[4]int]bool{}
+		mismatchWarnings = nil
+	})
+}
+
+func TestReferenceCalendar(t *testing.T) {
+	for jy := shamsy.MinYear; jy <= shamsy.MaxYear; jy++ {
+		for _, md := range [][2]int{{1, 1}, {6, 31}, {7, 1}, {12, shamsy.MonthDays(jy, 12)}} {
+			gy, gm, gd := shamsy.ToGregorian(jy, md[0], md[1])
+			if ry, rm, rd := referenceGregorian(jy, md[0], md[1]); ry != gy || rm != gm || rd != gd {
+				t.Fatalf("%d/%02d/%02d: reference %d-%02d-%02d, converter %d-%02d-%02d", jy, md[0], md[1], ry, rm, rd, gy, gm, gd)
+			}
+			if ry, rm, rd := referenceShamsi(gy, gm, gd); ry != jy || rm != md[0] || rd != md[1] {
+				t.Fatalf("%d-%02d-%02d: reference %d/%02d/%02d, want %d/%02d/%02d", gy, gm, gd, ry, rm, rd, jy, md[0], md[1])
+			}
+		}
+	}
+}
+
+func TestParanoidDetectsBrokenConverter(t *testing.T) {
+	tests := []struct {
+		name    string
+		breakIt func()
+		check   func() bool
+		want    string
+	}{
+		{
+			name: "Shamsi to Gregorian off by one day",
+			// Both directions are broken alike, so the round trip agrees
+			// and only the reference catches the error.
+			breakIt: func() {
+				toGregorian = func(jy, jm, jd int) (int, int, int) { return shamsy.ToGregorian(jy, jm, jd+1) }
+				fromGregorian = func(gy, gm, gd int) (int, int, int) {
+					jy, jm, jd := shamsy.FromGregorian(gy, gm, gd)
+					return jy, jm, jd - 1
+				}
+			},
+			check: func() bool { return shamsyMismatch(1404, 7, 10) },
+			want:  "candidates: 2025/10/03 or 2025/10/02 (reference)",
+		},
+		{
+			name: "Gregorian to Shamsi in the wrong year",
+			breakIt: func() {
+				fromGregorian = func(gy, gm, gd int) (int, int, int) {
+					jy, jm, jd := shamsy.FromGregorian(gy, gm, gd)
+					return jy + 1, jm, jd
+				}
+			},
+			check: func() bool { return gregorianMismatch(2025, 10, 2) },
+			want:  "candidates: 1405/07/10 or 1404/07/10 (reference)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetParanoid(t)
+			if tt.check() {
+				t.Fatal("the correct converter failed the check")
+			}
+			checkedConversions = map[[4]int]bool{}
+			tt.breakIt()
+			if !tt.check() {
+				t.Fatal("the broken converter passed the check")
+			}
+			if len(mismatchWarnings) != 1 || !strings.Contains(mismatchWarnings[0], tt.want) {
+				t.Fatalf("warnings = %q, want one containing %q", mismatchWarnings, tt.want)
+			}
+			// The result is memoized and reported once.
+			tt.check()
+			if len(mismatchWarnings) != 1 {
+				t.Errorf("got %d warnings after a second check, want 1", len(mismatchWarnings))
+			}
+			rec := recordStatus(t)
+			reportMismatches()
+			if len(rec.warnings) != 1 || !strings.Contains(rec.warnings[0], "conversion mismatch") {
+				t.Errorf("reportMismatches warned %q", rec.warnings)
+			}
+		})
+	}
+}
+
+func TestMismatchCell(t *testing.T) {
+	tests := []struct {
+		cell string
+		bad  bool
+		want string
+	}{
+		{" 12 ", false, " 12 "},
+		{" 12 ", true, "!12 "},
+		{"12", true, "12"},
+		{"", true, ""},
+	}
+	for _, tt := range tests {
+		if got := mismatchCell(tt.cell, tt.bad); got != tt.want {
+			t.Errorf("mismatchCell(%q, %v) = %q, want %q", tt.cell, tt.bad, got, tt.want)
+		}
+	}
+}
+
+func TestParanoidShowsCheckedConversion(t *testing.T) {
+	savedFixed := fixedOnly
+	fixedOnly = true
+	t.Cleanup(func() { fixedOnly = savedFixed })
+	tests := []struct {
+		name    string
+		breakIt func()
+		convert func() error
+		want    string
+	}{
+		{
+			name:    "Shamsi to Gregorian",
+			breakIt: func() { toGregorian = func(jy, jm, jd int) (int, int, int) { return shamsy.ToGregorian(jy, jm, jd+1) } },
+			convert: func() error { return handleConvertDate("1404/07/10", false) },
+			want:    "2025/10/03",
+		},
+		{
+			name: "Gregorian to Shamsi",
+			breakIt: func() {
+				fromGregorian = func(gy, gm, gd int) (int, int, int) {
+					jy, jm, jd := shamsy.FromGregorian(gy, gm, gd)
+					return jy + 1, jm, jd
+				}
+			},
+			convert: func() error { return handleConvertDate("2025-10-02", true) },
+			want:    "1405/07/10",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetParanoid(t)
+			recordStatus(t)
+			tt.breakIt()
+			var err error
+			// The output shows the date the cross-check failed on, not one
+			// converted separately, and marks it in the holiday color.
+			out := captureStdout(func() { err = tt.convert() })
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(out, tt.want) || !strings.Contains(out, rgb(offday, " !")) {
+				t.Errorf("output lacks %s marked with '!':\n%s", tt.want, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// plainFormat selects the plain renderer of --format plain.
+//
+// The plain format is stable: its output only changes along with its name,
+// so that generated calendars can be compared as snapshots and kept in
+// version control. A month is printed as
+//
+//	Mehr 1404
+//	Sh Ye Do Se Ch Pa Jo
+//	          1  2  3  4
+//	 5  6* 7  8  9 10 11
+//	...
+//
+// the title in English, then the weekday names and one line per week in
+// cells of exactly three characters: the day right-aligned in two followed
+// by '#' for the highlighted day (today), '*' for a holiday or a space. The
+// output is ASCII only, without colors or trailing whitespace, and ends every
+// line with a single LF. Months of a year are separated by an empty line.
+var plainFormat bool
+
+// parseFormat validates --format; "plain" is the only format so far.
+func parseFormat(s string) (bool, error) {
+	switch s {
+	case "":
+		return false, nil
+	case "plain":
+		return true, nil
+	}
+	return false, withCode(codeInvalidArgument, fmt.Errorf("invalid --format %q (want plain)", s))
+}
+
+// printPlainMonth prints a month in the plain format. suffix gives the
+// character following day d.
+func printPlainMonth(title string, labels []string, first, days int, suffix func(d int) byte) {
+	var out strings.Builder
+	out.WriteString(title + "\n")
+	out.WriteString(strings.Join(labels, " ") + "\n")
+	var line strings.Builder
+	line.WriteString(strings.Repeat("   ", first))
+	pos := first
+	for d := 1; d <= days; d++ {
+		fmt.Fprintf(&line, "%2d%c", d, suffix(d))
+		if pos++; pos%7 == 0 || d == days {
+			out.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+			line.Reset()
+		}
+	}
+	_, err := fmt.Print(out.String())
+	checkStdout(err)
+}
+
+// plainSuffix returns the suffix of a day in the plain format.
+func plainSuffix(d, highlight int, holiday bool) byte {
+	switch {
+	case d == highlight:
+		return '#'
+	case holiday:
+		return '*'
+	}
+	return ' '
+}
+
+// printShamsyPlain prints a Shamsi month in the plain format.
+func printShamsyPlain(jy, jm, highlight int, holidays *shamsy.HolidayCalendar) {
+	days := shamsy.MonthDays(jy, jm)
+	checkHighlight(highlight, days)
+	printPlainMonth(fmt.Sprintf("%s %d", shamsyMonths[jm-1], jy), weekDays, getFirstWeekday(jy, jm), days, func(d int) byte {
+		_, ok := holidays.IsHoliday(shamsy.Date{Year: jy, Month: jm, Day: d})
+		return plainSuffix(d, highlight, ok)
+	})
+}
+
+// printGregorianPlain prints a Gregorian month in the plain format, starting
+// the week on the day chosen with --week-start.
+func printGregorianPlain(year, month, highlight int, shamsyHolidays *shamsy.HolidayCalendar) {
+	days := gregorianMonthDays(year, month)
+	checkHighlight(highlight, days)
+	labels := make([]string, 7)
+	for wd, label := range gregorianWeekDays {
+		labels[gregorianColumn(time.Weekday(wd))] = label
+	}
+	printPlainMonth(fmt.Sprintf("%s %d", gregorianMonths[month-1], year), labels, getGregorianFirstWeekday(year, month), days, func(d int) byte {
+		_, ok := shamsyHolidays.IsGregorianHoliday(year, month, d)
+		return plainSuffix(d, highlight, ok)
+	})
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		plain   bool
+		wantErr bool
+	}{
+		{"", false, false},
+		{"plain", true, false},
+		{"Plain", false, true},
+		{"json", false, true},
+	}
+	for _, tt := range tests {
+		plain, err := parseFormat(tt.in)
+		if plain != tt.plain || (err != nil) != tt.wantErr {
+			t.Errorf("parseFormat(%q) = %v, %v", tt.in, plain, err)
+		}
+		if err != nil && errorCode(err) != codeInvalidArgument {
+			t.Errorf("parseFormat(%q): error code %s, want %s", tt.in, errorCode(err), codeInvalidArgument)
+		}
+	}
+}
+
+// The plain format is a stable interface: a change to these snapshots is a
+// breaking change.
+func TestPlainGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		render func()
+		want   string
+	}{
+		{"shamsi with holidays and today", func() {
+			printShamsyPlain(1404, 1, 13, fixedCalendar(t, 1404))
+		}, `Farvardin 1404
+Sh Ye Do Se Ch Pa Jo
+                   1*
+ 2* 3* 4* 5  6  7  8
+ 9 10 11 12*13#14 15
+16 17 18 19 20 21 22
+23 24 25 26 27 28 29
+30 31
+`},
+		{"shamsi without holidays", func() {
+			printShamsyPlain(1404, 7, noHighlight, nil)
+		}, `Mehr 1404
+Sh Ye Do Se Ch Pa Jo
+          1  2  3  4
+ 5  6  7  8  9 10 11
+12 13 14 15 16 17 18
+19 20 21 22 23 24 25
+26 27 28 29 30
+`},
+		{"gregorian starting on monday", func() {
+			setWeekStart(t, time.Monday)
+			printGregorianPlain(2025, 10, 2, nil)
+		}, `October 2025
+Mo Tu We Th Fr Sa Su
+       1  2# 3  4  5
+ 6  7  8  9 10 11 12
+13 14 15 16 17 18 19
+20 21 22 23 24 25 26
+27 28 29 30 31
+`},
+	}
+	for _, tt := range tests {
+		got := captureStdout(tt.render)
+		if got != tt.want {
+			t.Errorf("%s:\n%s\nwant:\n%s", tt.name, got, tt.want)
+		}
+		for _, line := range strings.Split(got, "\n") {
+			if strings.TrimRight(line, " ") != line || strings.ContainsAny(line, "\x1b\r") {
+				t.Errorf("%s: line %q has trailing spaces or control characters", tt.name, line)
+			}
+		}
+	}
+}
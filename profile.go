@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// profileConfig is one entry of the "profiles" section of the config: a
+// named set of settings selected together with --profile, e.g. one per
+// jurisdiction or team. Empty fields leave the setting alone.
+type profileConfig struct {
+	Region      string `json:"region"`
+	Gregorian   bool   `json:"gregorian"`
+	WeekdayLang string `json:"weekday_lang"`
+	MonthLang   string `json:"month_lang"`
+	HalfDay     string `json:"half_day"`
+	TZ          string `json:"tz"`
+}
+
+// applyProfile applies the settings of profile name as if they were given
+// as flags. Flags given before the arguments take precedence over the
+// profile, and those after them are parsed later and override it too.
+func applyProfile(name string) error {
+	profile, ok := userConfig.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(userConfig.Profiles))
+		for n := range userConfig.Profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return withCode(codeConfig, fmt.Errorf("unknown profile %q: the config defines no profiles", name))
+		}
+		return withCode(codeConfig, fmt.Errorf("unknown profile %q (defined: %s)", name, strings.Join(names, ", ")))
+	}
+	given := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { given[f.Name] = true })
+	settings := []struct{ flag, value string }{
+		{"region", profile.Region},
+		{"weekday-lang", profile.WeekdayLang},
+		{"month-lang", profile.MonthLang},
+		{"half-day", profile.HalfDay},
+		{"tz", profile.TZ},
+	}
+	if profile.Gregorian && !given["g"] {
+		settings = append(settings, struct{ flag, value string }{"gregorian", strconv.FormatBool(profile.Gregorian)})
+	}
+	for _, s := range settings {
+		if s.value == "" || given[s.flag] {
+			continue
+		}
+		if err := flag.Set(s.flag, s.value); err != nil {
+			return withCode(codeConfig, fmt.Errorf("invalid %s in profile %q: %v", s.flag, name, err))
+		}
+	}
+	return nil
+}
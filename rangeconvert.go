@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// conversionJSON is one day of --range-convert --json. Shamsi is YYYY/MM/DD
+// and Gregorian YYYY-MM-DD, as in the other JSON outputs.
+type conversionJSON struct {
+	Shamsi    string `json:"shamsi"`
+	Gregorian string `json:"gregorian"`
+	Weekday   string `json:"weekday"`
+}
+
+// rangeConversions returns every day from fromStr to toStr inclusive, both
+// Shamsi dates or, with isGregorian, Gregorian ones.
+func rangeConversions(fromStr, toStr string, isGregorian bool) ([]conversionJSON, error) {
+	_, _, _, from, err := parseCalendarDate(fromStr, isGregorian)
+	if err != nil {
+		return nil, err
+	}
+	_, _, _, to, err := parseCalendarDate(toStr, isGregorian)
+	if err != nil {
+		return nil, err
+	}
+	if from.EpochDays() > to.EpochDays() {
+		return nil, withCode(codeInvalidArgument, fmt.Errorf("start date %s is after end date %s", fromStr, toStr))
+	}
+	rows := make([]conversionJSON, 0, to.EpochDays()-from.EpochDays()+1)
+	for n := from.EpochDays(); n <= to.EpochDays(); n++ {
+		d := shamsy.DateFromEpochDays(n)
+		g := gregorianOf(d)
+		rows = append(rows, conversionJSON{
+			Shamsi:    d.String(),
+			Gregorian: shamsy.FormatGregorian(g.Year, g.Month, g.Day, "YYYY-MM-DD"),
+			Weekday:   shamsy.WeekdayName(g.Year, g.Month, g.Day),
+		})
+	}
+	return rows, nil
+}
+
+// handleRangeConvert prints the conversion of every day in a range as an
+// aligned table, or as CSV or JSON.
+func handleRangeConvert(fromStr, toStr string, isGregorian, asCSV bool) error {
+	if asCSV && jsonOutput {
+		return withCode(codeUsage, fmt.Errorf("--csv and --json cannot be combined"))
+	}
+	rows, err := rangeConversions(fromStr, toStr, isGregorian)
+	if err != nil {
+		return err
+	}
+	switch {
+	case jsonOutput:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return checkStdout(enc.Encode(rows))
+	case asCSV:
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"shamsi", "gregorian", "weekday"})
+		for _, r := range rows {
+			w.Write([]string{r.Shamsi, r.Gregorian, r.Weekday})
+		}
+		w.Flush()
+		return checkStdout(w.Error())
+	}
+	fmt.Printf("%s  %s  %s\n", rgb(green, fmt.Sprintf("%-10s", "Shamsi")), rgb(green, fmt.Sprintf("%-10s", "Gregorian")), rgb(green, "Weekday"))
+	fmt.Println(rgb(cyan, strings.Repeat("-", 33)))
+	for _, r := range rows {
+		if _, err := fmt.Printf("%s  %s  %s\n", rgb(yellow, r.Shamsi), rgb(blue, r.Gregorian), rgb(cyan, r.Weekday)); err != nil {
+			return checkStdout(err)
+		}
+	}
+	return nil
+}
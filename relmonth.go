@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// relativeMonth returns the month n months after the current one, or before
+// it for a negative n, in the Shamsi calendar or, with isGregorian, the
+// Gregorian one.
+func relativeMonth(n int, isGregorian bool) (int, int) {
+	today := currentDate()
+	y, m := today.Year, today.Month
+	if isGregorian {
+		g := gregorianOf(today)
+		y, m = g.Year, g.Month
+	}
+	months := y*12 + m - 1 + n
+	return months / 12, months%12 + 1
+}
+
+// relativeMonthArgs turns --prev N or --next N into the YEAR MONTH
+// arguments of the month they name.
+func relativeMonthArgs(prev, next string, isGregorian bool) ([]string, error) {
+	if prev != "" && next != "" {
+		return nil, withCode(codeUsage, fmt.Errorf("--prev and --next cannot be combined"))
+	}
+	name, value, sign := "--next", next, 1
+	if prev != "" {
+		name, value, sign = "--prev", prev, -1
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return nil, withCode(codeInvalidArgument, fmt.Errorf("invalid %s %q: must be a number of months", name, value))
+	}
+	y, m := relativeMonth(sign*n, isGregorian)
+	if !yearInRange(y, isGregorian) {
+		return nil, withCode(codeInvalidArgument, fmt.Errorf("%s %d leaves the supported range %s", name, n, supportedRange(isGregorian)))
+	}
+	return []string{strconv.Itoa(y), strconv.Itoa(m)}, nil
+}
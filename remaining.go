@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// handleRemaining prints how many days are left after today in the current
+// month and/or year, in the Shamsi calendar or, with -g, the Gregorian one.
+func handleRemaining(inMonth, inYear, isGregorian bool) {
+	now := currentTime()
+	gy, gm, gd := now.Year(), int(now.Month()), now.Day()
+	y, m, d := fromGregorian(gy, gm, gd)
+	monthDays, yearDays, dayOfYear := shamsy.MonthDays(y, m), 365, shamsyDayOfYear(m, d)
+	monthName := shamsyMonths[m-1]
+	if shamsy.IsLeapYear(y) {
+		yearDays = 366
+	}
+	if isGregorian {
+		y, m, d = gy, gm, gd
+		monthDays, yearDays, dayOfYear = gregorianMonthDays(y, m), 365, gregorianDayOfYear(y, m, d)
+		monthName = gregorianMonths[m-1]
+		if isGregorianLeapYear(y) {
+			yearDays = 366
+		}
+	}
+	if inMonth {
+		fmt.Printf("%s: %s\n", rgb(green, fmt.Sprintf("Remaining in %s %d", monthName, y)),
+			rgb(cyan, fmt.Sprintf("%d days", monthDays-d)))
+	}
+	if inYear {
+		fmt.Printf("%s: %s\n", rgb(green, fmt.Sprintf("Remaining in %d", y)),
+			rgb(cyan, fmt.Sprintf("%d days", yearDays-dayOfYear)))
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import "github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+
+// monthLayout selects how renderMonth draws a month.
+type monthLayout int
+
+const (
+	layoutGrid  monthLayout = iota // weeks as rows, the default
+	layoutNcal                     // weekdays as rows and weeks as columns (--ncal)
+	layoutPlain                    // the stable ASCII format of --format plain
+)
+
+// renderOptions describes a month view: the month and its calendar, the day
+// to highlight, the holidays to color and how to lay it out. The CLI fills
+// it in from flags and config and hands it to renderMonth, so a new
+// rendering feature is a field here rather than another parameter of every
+// renderer.
+//
+// Settings that apply alike to every view of a run stay package settings:
+// the palette (palette.go), the Gregorian week start (weekstart.go), the
+// holiday marker (marker.go) and the label languages (lang.go).
+type renderOptions struct {
+	Gregorian   bool // a Gregorian month; Year and Month are Gregorian
+	Year, Month int
+	Highlight   int // day to highlight, or noHighlight
+	Holidays    *shamsy.HolidayCalendar
+	Layout      monthLayout
+	monthOptions
+}
+
+// renderMonth prints the month described by r.
+func renderMonth(r renderOptions) {
+	switch {
+	case r.Layout == layoutPlain && r.Gregorian:
+		printGregorianPlain(r.Year, r.Month, r.Highlight, r.Holidays)
+	case r.Layout == layoutPlain:
+		printShamsyPlain(r.Year, r.Month, r.Highlight, r.Holidays)
+	case r.Layout == layoutNcal && r.Gregorian:
+		printGregorianNcal(r.Year, r.Month, r.Highlight, r.Holidays, r.monthOptions)
+	case r.Gregorian:
+		printGregorianCalendar(r.Year, r.Month, r.Highlight, r.Holidays, r.monthOptions)
+	case r.Layout == layoutNcal:
+		printshamsyNcal(r.Year, r.Month, r.Highlight, r.Holidays, r.monthOptions)
+	default:
+		printshamsyCalendar(r.Year, r.Month, r.Highlight, r.Holidays, r.monthOptions)
+	}
+}
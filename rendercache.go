@@ -0,0 +1,94 @@
+package main
+
+import (
+	"crypto/sha256"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// renderCacheEnabled makes month views reuse previously rendered output.
+var renderCacheEnabled bool
+
+// renderCacheKey identifies a rendered view by the calendar and layout flags,
+// the view (see monthView), the highlighted day, the terminal width, the
+// colors resolved from the environment (depth, palette, NO_COLOR) and the
+// modification times of the holiday caches (month files included) and the
+// config file it was built from. Refreshing the holidays of a year or editing
+// the rules therefore invalidates every view that used them.
+func renderCacheKey(view []string, highlight int, holidayYears []int) string {
+	h := sha256.New()
+	flag.VisitAll(func(f *flag.Flag) {
+		fmt.Fprintf(h, "%s=%s\n", f.Name, f.Value.String())
+	})
+	fmt.Fprintf(h, "view=%s\nhighlight=%d\nwidth=%d\n", strings.Join(view, " "), highlight, terminalWidth())
+	fmt.Fprintf(h, "color_depth=%s\nlight_palette=%v\nno_color=%v\n", colorDepth, lightPalette, noColor)
+	for _, year := range holidayYears {
+		if modTime, ok := holidayOptions.CacheModTime(year); ok {
+			fmt.Fprintf(h, "holidays_%d=%d\n", year, modTime.UnixNano())
+		}
+	}
+	if f := flag.Lookup("config"); f != nil {
+		if path, err := configFile(f.Value.String()); err == nil {
+			if info, err := os.Stat(path); err == nil {
+				fmt.Fprintf(h, "config=%d/%d\n", info.ModTime().UnixNano(), info.Size())
+			}
+		}
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))[:32]
+}
+
+// monthView identifies the month view of a calendar for renderCached. The
+// month is the one shown, not the arguments it was selected by: without
+// arguments it changes with the date.
+func monthView(gregorian bool, year, month int) []string {
+	calendar := "shamsi"
+	if gregorian {
+		calendar = "gregorian"
+	}
+	return []string{calendar, strconv.Itoa(year), strconv.Itoa(month)}
+}
+
+// renderCacheFile returns where the view with the given key is cached.
+func renderCacheFile(key string) (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "render", key+".txt"), nil
+}
+
+// renderCached prints the view produced by render. With --render-cache the
+// output is served from the render cache when an identical view was rendered
+// before, skipping holiday fetching and rendering entirely.
+func renderCached(view []string, highlight int, holidayYears []int, render func()) {
+	if !renderCacheEnabled {
+		render()
+		return
+	}
+	if cacheFile, err := renderCacheFile(renderCacheKey(view, highlight, holidayYears)); err == nil {
+		if data, err := os.ReadFile(cacheFile); err == nil {
+			_, err = fmt.Print(string(data))
+			checkStdout(err)
+			return
+		}
+	}
+	out := captureStdout(render)
+	_, err := fmt.Print(out)
+	checkStdout(err)
+	// Rendering may have created the holiday caches, so the key is computed
+	// again for storing.
+	cacheFile, err := renderCacheFile(renderCacheKey(view, highlight, holidayYears))
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(cacheFile), 0755)
+	}
+	if err == nil {
+		err = os.WriteFile(cacheFile, []byte(out), 0644)
+	}
+	if err != nil {
+		status.Warn(fmt.Sprintf("failed to save rendered view: %v", err))
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenderCacheKeyMonth(t *testing.T) {
+	holidayOptions.CacheDir = t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	keys := map[string][]string{}
+	for _, view := range [][]string{
+		monthView(false, 1404, 7),
+		monthView(false, 1404, 8),
+		monthView(false, 1405, 7),
+		monthView(true, 2025, 7),
+		monthView(true, 2025, 8),
+	} {
+		// The same highlighted day in every month, as on the 5th of each.
+		key := renderCacheKey(view, 5, []int{1404})
+		if other, ok := keys[key]; ok {
+			t.Errorf("views %v and %v share the key %s", other, view, key)
+		}
+		keys[key] = view
+	}
+	if a, b := renderCacheKey(monthView(false, 1404, 7), 5, nil), renderCacheKey(monthView(false, 1404, 7), 5, nil); a != b {
+		t.Errorf("the key of a view changed between calls: %s and %s", a, b)
+	}
+}
+
+func TestRenderCacheKeyMonthFiles(t *testing.T) {
+	holidayOptions.CacheDir = t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	view := monthView(false, 1404, 7)
+	before := renderCacheKey(view, 5, []int{1404})
+	path := filepath.Join(holidayOptions.CacheDir, "holidays_1404_07.json")
+	if err := os.WriteFile(path, []byte(`{"1404-07-01": "Holiday"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if after := renderCacheKey(view, 5, []int{1404}); after == before {
+		t.Error("caching the month's holidays did not change the key")
+	}
+}
+
+func TestRenderCacheKeyColors(t *testing.T) {
+	holidayOptions.CacheDir = t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	savedDepth, savedLight, savedNoColor := colorDepth, lightPalette, noColor
+	t.Cleanup(func() { colorDepth, lightPalette, noColor = savedDepth, savedLight, savedNoColor })
+
+	view := monthView(false, 1404, 7)
+	keys := map[string]string{}
+	for _, tt := range []struct {
+		depth          string
+		light, noColor bool
+	}{
+		{depthTrueColor, false, false},
+		{depth16, false, false},
+		{depth256, false, false},
+		{depthTrueColor, true, false},
+		{depthTrueColor, false, true},
+	} {
+		colorDepth, lightPalette, noColor = tt.depth, tt.light, tt.noColor
+		name := fmt.Sprintf("depth %s, light %v, no color %v", tt.depth, tt.light, tt.noColor)
+		key := renderCacheKey(view, 5, nil)
+		if other, ok := keys[key]; ok {
+			t.Errorf("%s and %s share the key %s", other, name, key)
+		}
+		keys[key] = name
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// holidayRule is a parsed recurring off day from the config's rules.
+type holidayRule struct {
+	Match string
+	Name  string
+	match func(d shamsy.Date) bool
+}
+
+// holidayRules are the rules from the configuration, in config order.
+var holidayRules []holidayRule
+
+// ordinals are the words accepted before a weekday in "... of month" rules.
+var ordinals = map[string]int{"first": 1, "second": 2, "third": 3, "fourth": 4, "last": -1}
+
+// dateJDN returns the Julian Day Number of a Shamsi date.
+func dateJDN(d shamsy.Date) int {
+	return shamsy.GregorianJDN(toGregorian(d.Year, d.Month, d.Day))
+}
+
+// parseRule parses a rule expression. The supported forms are:
+//
+//	every WEEKDAY
+//	every N WEEKDAY starting YYYY/MM/DD
+//	first|second|third|fourth|last WEEKDAY of month
+//
+// Months and start dates are Shamsi.
+func parseRule(expr string) (func(d shamsy.Date) bool, error) {
+	fields := strings.Fields(strings.ToLower(expr))
+	invalid := withCode(codeConfig, fmt.Errorf("invalid rule %q", expr))
+	switch {
+	case len(fields) == 2 && fields[0] == "every":
+		wd, ok := parseWeekday(fields[1])
+		if !ok {
+			return nil, invalid
+		}
+		return func(d shamsy.Date) bool { return d.Weekday() == wd }, nil
+	case len(fields) == 5 && fields[0] == "every" && fields[3] == "starting":
+		n, err := strconv.Atoi(fields[1])
+		wd, ok := parseWeekday(fields[2])
+		if err != nil || n < 1 || !ok {
+			return nil, invalid
+		}
+		sy, sm, sd, err := parseShamsyDate(fields[4])
+		if err != nil {
+			return nil, withCode(codeConfig, fmt.Errorf("invalid rule %q: %v", expr, err))
+		}
+		start := shamsy.Date{Year: sy, Month: sm, Day: sd}
+		for start.Weekday() != wd {
+			start = start.Next()
+		}
+		first := dateJDN(start)
+		return func(d shamsy.Date) bool {
+			diff := dateJDN(d) - first
+			return diff >= 0 && diff%(7*n) == 0
+		}, nil
+	case len(fields) == 4 && fields[2] == "of" && fields[3] == "month":
+		k, ok := ordinals[fields[0]]
+		wd, wdOK := parseWeekday(fields[1])
+		if !ok || !wdOK {
+			return nil, invalid
+		}
+		return func(d shamsy.Date) bool {
+			if d.Weekday() != wd {
+				return false
+			}
+			if k < 0 {
+				return d.Day+7 > shamsy.MonthDays(d.Year, d.Month)
+			}
+			return (d.Day-1)/7 == k-1
+		}, nil
+	}
+	return nil, invalid
+}
+
+// loadRules parses the rules of the configuration.
+func loadRules(cfg config) ([]holidayRule, error) {
+	var rules []holidayRule
+	for _, rc := range cfg.Rules {
+		match, err := parseRule(rc.Match)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, holidayRule{Match: rc.Match, Name: rc.Name, match: match})
+	}
+	return rules, nil
+}
+
+// ruleHolidays returns the days of a Shamsi year matched by the rules. When
+// several rules match a day the first one names it.
+func ruleHolidays(jy int) []shamsy.Holiday {
+	if len(holidayRules) == 0 {
+		return nil
+	}
+	var holidays []shamsy.Holiday
+	for d := (shamsy.Date{Year: jy, Month: 1, Day: 1}); d.Year == jy; d = d.Next() {
+		for _, r := range holidayRules {
+			if r.match(d) {
+				holidays = append(holidays, shamsy.Holiday{Date: d, Name: r.Name})
+				break
+			}
+		}
+	}
+	return holidays
+}
+
+// handleRules implements "rules test YEAR/MONTH", listing the days of a
+// Shamsi month each configured rule matches.
+func handleRules(args []string) error {
+	fs := flag.NewFlagSet("rules", flag.ContinueOnError)
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 || positional[0] != "test" {
+		return withCode(codeUsage, fmt.Errorf("usage: shamsy-calendar rules test YEAR/MONTH"))
+	}
+	parts := strings.Split(strings.ReplaceAll(positional[1], "-", "/"), "/")
+	if len(parts) != 2 {
+		return withCode(codeInvalidArgument, fmt.Errorf("invalid month %q, expected YEAR/MONTH", positional[1]))
+	}
+	jy, jm, err := parseYearMonth(parts[0], parts[1])
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(rgb(cyan, strings.Repeat("=", 60)))
+	fmt.Println(rgb(purple, withIcon("📏", fmt.Sprintf("Rules in %s %d", shamsyMonths[jm-1], jy))))
+	fmt.Println(rgb(cyan, strings.Repeat("-", 60)))
+	if len(holidayRules) == 0 {
+		fmt.Println("No rules configured.")
+	}
+	for _, r := range holidayRules {
+		fmt.Printf("%s = %s\n", rgb(green, r.Match), rgb(offday, r.Name))
+		found := false
+		for d := 1; d <= shamsy.MonthDays(jy, jm); d++ {
+			date := shamsy.Date{Year: jy, Month: jm, Day: d}
+			if r.match(date) {
+				fmt.Printf("  %s  %s\n", rgb(yellow, date.String()), rgb(cyan, gregorianOf(date).DayWeek))
+				found = true
+			}
+		}
+		if !found {
+			fmt.Println("  (no matches)")
+		}
+	}
+	fmt.Println(rgb(cyan, strings.Repeat("=", 60)))
+	return nil
+}
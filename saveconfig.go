@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// settingFlags are the flags that can be saved as defaults with
+// --save-config: those that change how scal looks or behaves, as opposed to
+// the ones selecting what it does (-c, --agenda, ...) or that only make
+// sense once (--refresh, --output, ...).
+var settingFlags = map[string]bool{
+	"gregorian": true, "ncal": true, "half-day": true, "pad-adjacent": true,
+	"marker": true, "weekday-lang": true, "month-lang": true, "week-numbers": true,
+	"week-start": true, "weekstart-sunday": true, "rainbow-weekdays": true,
+	"quarter-grid": true, "no-header": true, "no-weekday-header": true,
+	"format": true, "show-length": true, "bare": true, "gap": true, "separator": true,
+	"strict-width": true, "no-trailing-newline": true, "emoji-holidays": true, "ascii": true,
+	"translate": true, "observed": true, "render-cache": true, "no-summary": true,
+	"light": true, "dark": true, "no-color": true, "color-depth": true,
+	"api-url": true, "tz": true, "region": true, "cache-dir": true, "forecast": true,
+	"fixed-only": true, "no-history": true, "week-date": true, "paranoid": true,
+	"verbose": true, "gregorian-events": true,
+}
+
+// flagAliases map shorthand flags to the name they are saved under.
+var flagAliases = map[string]string{"g": "gregorian", "fiscal": "quarter-grid"}
+
+// saveConfigFromFlags implements --save-config: the setting flags given on
+// the command line are merged into the "defaults" section of the config
+// file, which is created when missing. Other keys of the file are kept as
+// they are. args are the positional arguments, which must be empty.
+func saveConfigFromFlags(override string, args []string) error {
+	if len(args) > 0 {
+		return withCode(codeUsage, fmt.Errorf("--save-config takes no arguments; give only the flags to save"))
+	}
+	saved := map[string]string{}
+	var skipped []string
+	flag.Visit(func(f *flag.Flag) {
+		name := f.Name
+		if alias, ok := flagAliases[name]; ok {
+			name = alias
+		}
+		switch {
+		case name == "save-config" || name == "config":
+		case settingFlags[name]:
+			saved[name] = f.Value.String()
+		default:
+			skipped = append(skipped, "--"+f.Name)
+		}
+	})
+	if len(saved) == 0 {
+		return withCode(codeUsage, fmt.Errorf("--save-config: no setting flags given, e.g. scal -g --week-start monday --save-config"))
+	}
+
+	path, err := configFile(override)
+	if err != nil {
+		return err
+	}
+	raw := map[string]json.RawMessage{}
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return withCode(codeConfig, fmt.Errorf("invalid config %s: %v", path, err))
+		}
+	case !os.IsNotExist(err):
+		return withCode(codeConfig, fmt.Errorf("failed to read config: %v", err))
+	}
+	defaults := map[string]string{}
+	if existing, ok := raw["defaults"]; ok {
+		if err := json.Unmarshal(existing, &defaults); err != nil {
+			return withCode(codeConfig, fmt.Errorf("invalid \"defaults\" in config %s: %v", path, err))
+		}
+	}
+	for name, value := range saved {
+		defaults[name] = value
+	}
+	if raw["defaults"], err = json.Marshal(defaults); err != nil {
+		return err
+	}
+	out, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return withCode(codeConfig, fmt.Errorf("failed to save config: %v", err))
+	}
+	if err := os.WriteFile(path, append(out, '\n'), 0644); err != nil {
+		return withCode(codeConfig, fmt.Errorf("failed to save config: %v", err))
+	}
+
+	names := make([]string, 0, len(saved))
+	for name := range saved {
+		names = append(names, "--"+name)
+	}
+	sort.Strings(names)
+	fmt.Printf("%s: %s\n", rgb(green, "Saved to "+path), rgb(cyan, strings.Join(names, " ")))
+	if len(skipped) > 0 {
+		sort.Strings(skipped)
+		status.Warn(fmt.Sprintf("not saved, as they are not settings: %s", strings.Join(skipped, " ")))
+	}
+	return nil
+}
+
+// applyDefaults applies the "defaults" section of the config as if its
+// entries were given as flags, below the flags of the command line and
+// of --profile.
+func applyDefaults() error {
+	given := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { given[f.Name] = true })
+	names := make([]string, 0, len(userConfig.Defaults))
+	for name := range userConfig.Defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !settingFlags[name] {
+			return withCode(codeConfig, fmt.Errorf("invalid \"defaults\" in config: --%s is not a setting", name))
+		}
+		if given[name] || given[reverseAlias(name)] {
+			continue
+		}
+		if err := flag.Set(name, userConfig.Defaults[name]); err != nil {
+			return withCode(codeConfig, fmt.Errorf("invalid \"defaults\" in config: --%s: %v", name, err))
+		}
+	}
+	return nil
+}
+
+// reverseAlias returns the shorthand of a flag saved under name, or "".
+func reverseAlias(name string) string {
+	for alias, target := range flagAliases {
+		if target == name {
+			return alias
+		}
+	}
+	return ""
+}
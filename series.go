@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// handleWeekdaySeries prints the weekday a month/day falls on in every year
+// from fromStr to toStr, e.g. a birthday over the next decade.
+func handleWeekdaySeries(monthDay, fromStr, toStr string, isGregorian bool) error {
+	parts := strings.Split(strings.ReplaceAll(monthDay, "-", "/"), "/")
+	if len(parts) != 2 {
+		return withCode(codeInvalidArgument, fmt.Errorf("invalid month/day %q, expected MM/DD", monthDay))
+	}
+	month, err1 := strconv.Atoi(parts[0])
+	day, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil || month < 1 || month > 12 || day < 1 || day > 31 {
+		return withCode(codeInvalidArgument, fmt.Errorf("invalid month/day %q, expected MM/DD", monthDay))
+	}
+	from, err1 := strconv.Atoi(fromStr)
+	to, err2 := strconv.Atoi(toStr)
+	if err1 != nil || err2 != nil || to < from || !yearInRange(from, isGregorian) || !yearInRange(to, isGregorian) {
+		return withCode(codeInvalidArgument, fmt.Errorf("invalid year range %s–%s", fromStr, toStr))
+	}
+
+	fmt.Println(rgb(cyan, strings.Repeat("=", 60)))
+	if isGregorian {
+		fmt.Println(rgb(purple, withIcon("📅", fmt.Sprintf("%s %d, %d–%d", gregorianMonths[month-1], day, from, to))))
+	} else {
+		fmt.Println(rgb(purple, withIcon("📅", fmt.Sprintf("%d %s, %d–%d", day, shamsyMonths[month-1], from, to))))
+	}
+	fmt.Println(rgb(cyan, strings.Repeat("-", 60)))
+	for y := from; y <= to; y++ {
+		var err error
+		if isGregorian {
+			if day > gregorianMonthDays(y, month) {
+				fmt.Printf("%s  %s\n", rgb(blue, shamsy.FormatGregorian(y, month, day, "YYYY/MM/DD")), rgb(offday, "(no such day this year)"))
+				continue
+			}
+			sh := shamsiDateInfo(y, month, day)
+			_, err = fmt.Printf("%s  %s  %s\n", rgb(blue, shamsy.FormatGregorian(y, month, day, "YYYY/MM/DD")),
+				rgb(yellow, shamsy.FormatShamsi(sh.Year, sh.Month, sh.Day, "YYYY/MM/DD")), rgb(cyan, sh.DayWeek))
+		} else {
+			if day > shamsy.MonthDays(y, month) {
+				fmt.Printf("%s  %s\n", rgb(yellow, shamsy.FormatShamsi(y, month, day, "YYYY/MM/DD")), rgb(offday, "(no such day this year)"))
+				continue
+			}
+			g := gregorianDateInfo(y, month, day)
+			_, err = fmt.Printf("%s  %s  %s\n", rgb(yellow, shamsy.FormatShamsi(y, month, day, "YYYY/MM/DD")),
+				rgb(blue, shamsy.FormatGregorian(g.Year, g.Month, g.Day, "YYYY/MM/DD")), rgb(cyan, g.DayWeek))
+		}
+		if err != nil {
+			return checkStdout(err)
+		}
+	}
+	fmt.Println(rgb(cyan, strings.Repeat("=", 60)))
+	return nil
+}
@@ -0,0 +1,199 @@
+package shamsy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultCacheDir returns the directory holiday caches are kept in when
+// Options.CacheDir is empty. XDG_CACHE_HOME is honored on every platform,
+// not only where os.UserCacheDir already does so.
+func DefaultCacheDir() (string, error) {
+	cacheDir := os.Getenv("XDG_CACHE_HOME")
+	if cacheDir == "" {
+		var err error
+		if cacheDir, err = os.UserCacheDir(); err != nil {
+			return "", fmt.Errorf("failed to get cache directory: %v", err)
+		}
+	}
+	return filepath.Join(cacheDir, "shamsy_calendar"), nil
+}
+
+// cacheStore keeps the holiday entries of each Shamsi year in its own JSON
+// file. Months fetched on their own are kept in month files, which a later
+// fetch of the whole year supersedes. The directory is only created when
+// something is written.
+type cacheStore struct {
+	dir string
+}
+
+// store returns the cache store selected by o. Regions other than
+// DefaultRegion get a subdirectory so that their holidays never mix.
+func (o Options) store() (cacheStore, error) {
+	dir := o.CacheDir
+	if dir == "" {
+		var err error
+		if dir, err = DefaultCacheDir(); err != nil {
+			return cacheStore{}, err
+		}
+	}
+	if region := normalizeRegion(o.Region); region != DefaultRegion {
+		dir = filepath.Join(dir, region)
+	}
+	return cacheStore{dir: dir}, nil
+}
+
+// CacheFile returns the path of the cached holidays of a Shamsi year.
+func (o Options) CacheFile(year int) (string, error) {
+	store, err := o.store()
+	if err != nil {
+		return "", err
+	}
+	return store.file(year), nil
+}
+
+// CacheModTime returns when the cached holidays of a Shamsi year last
+// changed: the newest modification time of its year file and month files.
+// ok is false when nothing of the year is cached.
+func (o Options) CacheModTime(year int) (modTime time.Time, ok bool) {
+	store, err := o.store()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return store.modTime(year)
+}
+
+func (c cacheStore) file(year int) string {
+	return filepath.Join(c.dir, fmt.Sprintf("holidays_%d.json", year))
+}
+
+func (c cacheStore) monthFile(year, month int) string {
+	return filepath.Join(c.dir, fmt.Sprintf("holidays_%d_%02d.json", year, month))
+}
+
+// modTime implements Options.CacheModTime.
+func (c cacheStore) modTime(year int) (modTime time.Time, ok bool) {
+	paths := []string{c.file(year)}
+	for month := 1; month <= 12; month++ {
+		paths = append(paths, c.monthFile(year, month))
+	}
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil && (!ok || info.ModTime().After(modTime)) {
+			modTime, ok = info.ModTime(), true
+		}
+	}
+	return modTime, ok
+}
+
+// read returns the cached entries of a year. Month files written after the
+// year file replace the year's entries for their month, so the freshest data
+// wins.
+func (c cacheStore) read(year int) (map[string]string, error) {
+	entries, yearTime, err := readEntries(c.file(year))
+	if err != nil {
+		return nil, err
+	}
+	for month := 1; month <= 12; month++ {
+		monthEntries, monthTime, err := readEntries(c.monthFile(year, month))
+		if err != nil || !monthTime.After(yearTime) {
+			continue
+		}
+		for k := range entries {
+			if keyMonth(k) == (Date{Year: year, Month: month}) {
+				delete(entries, k)
+			}
+		}
+		for k, v := range monthEntries {
+			entries[k] = v
+		}
+	}
+	return entries, nil
+}
+
+// readMonth returns cached entries that cover a month: those of the year
+// when it is cached, otherwise those of the month file.
+func (c cacheStore) readMonth(year, month int) (map[string]string, error) {
+	if entries, err := c.read(year); err == nil {
+		return entries, nil
+	}
+	entries, _, err := readEntries(c.monthFile(year, month))
+	return entries, err
+}
+
+// write caches the entries of a whole year; the month files of the year are
+// superseded and removed.
+func (c cacheStore) write(year int, entries map[string]string) error {
+	if err := writeEntries(c.dir, c.file(year), entries); err != nil {
+		return err
+	}
+	for month := 1; month <= 12; month++ {
+		os.Remove(c.monthFile(year, month))
+	}
+	return nil
+}
+
+// writeMonth caches the entries of a single month.
+func (c cacheStore) writeMonth(year, month int, entries map[string]string) error {
+	return writeEntries(c.dir, c.monthFile(year, month), entries)
+}
+
+func readEntries(path string) (map[string]string, time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	var entries map[string]string
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, time.Time{}, err
+	}
+	return entries, info.ModTime(), nil
+}
+
+// writeEntries stores entries in path. An empty result never replaces a
+// cache file that holds holidays, which would hide them until the next
+// successful fetch.
+func writeEntries(dir, path string, entries map[string]string) error {
+	if len(entries) == 0 {
+		if cached, _, err := readEntries(path); err == nil && len(cached) > 0 {
+			return fmt.Errorf("refusing to replace the %d cached holidays in %s with an empty result", len(cached), path)
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %v", err)
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to marshal holidays to JSON: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cache file: %v", err)
+	}
+	return nil
+}
+
+// keyMonth returns the Shamsi year and month (Day 0) a cache key falls in,
+// converting the Gregorian keys.
+func keyMonth(key string) Date {
+	var y, m, d int
+	if _, err := fmt.Sscanf(key, "g:%d-%d-%d", &y, &m, &d); err == nil {
+		jy, jm, _ := FromGregorian(y, m, d)
+		return Date{Year: jy, Month: jm}
+	}
+	if _, err := fmt.Sscanf(key, "%d-%d-%d", &y, &m, &d); err == nil {
+		return Date{Year: y, Month: m}
+	}
+	return Date{}
+}
+
+// gregorianKey is the key under which a holiday is cached by its Gregorian
+// date, as reported by the provider. Shamsi keys have no prefix.
+func gregorianKey(gy, gm, gd int) string {
+	return fmt.Sprintf("g:%d-%02d-%02d", gy, gm, gd)
+}
@@ -0,0 +1,250 @@
+package shamsy
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// apiOptions returns Options that cache in a fresh directory and ask only
+// api.
+func apiOptions(t *testing.T, api *fakeAPI) Options {
+	return Options{CacheDir: t.TempDir(), Providers: []Provider{APIProvider{URL: api.URL}}}
+}
+
+func TestLoadHolidaysCacheMissThenHit(t *testing.T) {
+	api := newFakeAPI(t, testHolidays)
+	opts := apiOptions(t, api)
+	for i := 0; i < 2; i++ {
+		cal, err := LoadHolidays(context.Background(), 1404, opts)
+		if err != nil {
+			t.Fatalf("load %d: %v", i+1, err)
+		}
+		if name, ok := cal.IsHoliday(Date{Year: 1404, Month: 4, Day: 15}); !ok || name != "Ashura" {
+			t.Errorf("load %d: IsHoliday(1404/04/15) = %q, %v", i+1, name, ok)
+		}
+	}
+	if n := api.requests.Load(); n != 1 {
+		t.Errorf("the API was asked %d times, want 1: the second load should hit the cache", n)
+	}
+	if _, err := os.Stat(testStore(t, opts).file(1404)); err != nil {
+		t.Errorf("the year was not cached: %v", err)
+	}
+}
+
+func TestLoadHolidaysCorruptedCache(t *testing.T) {
+	api := newFakeAPI(t, testHolidays)
+	opts := apiOptions(t, api)
+	store := testStore(t, opts)
+	if err := os.WriteFile(store.file(1404), []byte(`{"1404-01-01": "Nowr`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cal, err := LoadHolidays(context.Background(), 1404, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cal.IsHoliday(Date{Year: 1404, Month: 4, Day: 15}); !ok {
+		t.Error("the holidays were not fetched again")
+	}
+	if _, err := store.read(1404); err != nil {
+		t.Errorf("the corrupted cache file was not replaced: %v", err)
+	}
+	if n := api.requests.Load(); n != 1 {
+		t.Errorf("the API was asked %d times, want 1", n)
+	}
+}
+
+func TestLoadHolidaysServerError(t *testing.T) {
+	api := newFakeAPI(t, testHolidays)
+	api.code = http.StatusInternalServerError
+	opts := apiOptions(t, api)
+	if _, err := LoadHolidays(context.Background(), 1404, opts); err == nil {
+		t.Fatal("expected an error for a 500 response")
+	}
+	if _, err := os.Stat(testStore(t, opts).file(1404)); !os.IsNotExist(err) {
+		t.Errorf("a failed fetch left a cache file behind: %v", err)
+	}
+
+	// The next provider is asked when one fails.
+	good := newFakeAPI(t, testHolidays)
+	opts.Providers = append(opts.Providers, APIProvider{URL: good.URL})
+	if _, err := LoadHolidays(context.Background(), 1404, opts); err != nil {
+		t.Fatalf("the second provider was not asked: %v", err)
+	}
+	if api.requests.Load() != 2 || good.requests.Load() != 1 {
+		t.Errorf("requests = %d and %d, want 2 and 1", api.requests.Load(), good.requests.Load())
+	}
+}
+
+func TestLoadHolidaysSlowResponse(t *testing.T) {
+	api := newFakeAPI(t, testHolidays)
+	api.delay = 10 * time.Second
+	opts := apiOptions(t, api)
+	var stages []string
+	opts.Progress = func(stage string) { stages = append(stages, stage) }
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := LoadHolidays(ctx, 1404, opts); err == nil {
+		t.Fatal("expected the timeout to fail the load")
+	}
+	if len(stages) != 2 || stages[0] != StageFetch || stages[1] != StageDone {
+		t.Errorf("progress stages = %v, want [%s %s]", stages, StageFetch, StageDone)
+	}
+}
+
+func TestLoadHolidaysOfflineMiss(t *testing.T) {
+	api := newFakeAPI(t, testHolidays)
+	opts := apiOptions(t, api)
+	opts.Offline = true
+	if _, err := LoadHolidays(context.Background(), 1404, opts); err == nil {
+		t.Error("expected an error for an uncached year when offline")
+	}
+	if n := api.requests.Load(); n != 0 {
+		t.Errorf("the API was asked %d times while offline", n)
+	}
+}
+
+func TestLoadHolidaysMultiYearMerge(t *testing.T) {
+	api := newFakeAPI(t, testHolidays)
+	opts := apiOptions(t, api)
+	var cals []*HolidayCalendar
+	for _, year := range []int{1403, 1404} {
+		cal, err := LoadHolidays(context.Background(), year, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cals = append(cals, cal)
+	}
+	merged := Merge(cals...)
+	holidays := merged.Holidays()
+	if len(holidays) != len(testHolidays) {
+		t.Fatalf("merged %d holidays, want %d: %v", len(holidays), len(testHolidays), holidays)
+	}
+	for i, h := range holidays {
+		if testHolidays[h.Date] != h.Name {
+			t.Errorf("unexpected holiday %v %q", h.Date, h.Name)
+		}
+		if i > 0 && holidays[i-1].Date.Compare(h.Date) >= 0 {
+			t.Errorf("holidays out of order: %v before %v", holidays[i-1].Date, h.Date)
+		}
+	}
+	// The Gregorian keys of both years are kept.
+	g := Date{Year: 1403, Month: 4, Day: 25}.Gregorian()
+	if _, ok := merged.IsGregorianHoliday(g.Year, g.Month, g.Day); !ok {
+		t.Errorf("IsGregorianHoliday(%d-%02d-%02d) = false after the merge", g.Year, g.Month, g.Day)
+	}
+	if n := api.requests.Load(); n != 2 {
+		t.Errorf("the API was asked %d times, want once per year", n)
+	}
+}
+
+// testStore returns the cache store of o, failing the test on error.
+func testStore(t *testing.T, o Options) cacheStore {
+	t.Helper()
+	store, err := o.store()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestCacheStoreMonthPrecedence(t *testing.T) {
+	store := cacheStore{dir: t.TempDir()}
+	year := map[string]string{"1404-01-01": "Nowruz", "1404-04-15": "Ashura"}
+	month := map[string]string{"1404-04-16": "Ashura"}
+	old := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		monthNewer bool
+		want       map[string]string
+	}{
+		{"newer month file wins", true, map[string]string{"1404-01-01": "Nowruz", "1404-04-16": "Ashura"}},
+		{"older month file is ignored", false, year},
+	}
+	for _, tt := range tests {
+		if err := store.writeMonth(1404, 4, month); err != nil {
+			t.Fatal(err)
+		}
+		if err := writeEntries(store.dir, store.file(1404), year); err != nil {
+			t.Fatal(err)
+		}
+		older := store.file(1404)
+		if !tt.monthNewer {
+			older = store.monthFile(1404, 4)
+		}
+		if err := os.Chtimes(older, old, old); err != nil {
+			t.Fatal(err)
+		}
+		got, err := store.read(1404)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: read = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// A full-year write supersedes the month files.
+	if err := store.write(1404, year); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(store.monthFile(1404, 4)); !os.IsNotExist(err) {
+		t.Errorf("the month file survived a year write: %v", err)
+	}
+}
+
+func TestCacheModTime(t *testing.T) {
+	opts := Options{CacheDir: t.TempDir()}
+	store := testStore(t, opts)
+	if _, ok := opts.CacheModTime(1404); ok {
+		t.Error("CacheModTime reported an uncached year")
+	}
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := writeEntries(store.dir, store.file(1404), map[string]string{"1404-01-01": "Nowruz"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(store.file(1404), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := opts.CacheModTime(1404); !ok || !got.Equal(old) {
+		t.Errorf("CacheModTime = %v, %v, want %v", got, ok, old)
+	}
+	// Caching a month changes the time, as the month file overrides the year.
+	if err := store.writeMonth(1404, 7, map[string]string{"1404-07-01": "Holiday"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := opts.CacheModTime(1404); !ok || !got.After(old) {
+		t.Errorf("CacheModTime = %v, %v after writing a month file, want later than %v", got, ok, old)
+	}
+}
+
+func TestEmptyResultKeepsCache(t *testing.T) {
+	api := newFakeAPI(t, testHolidays)
+	opts := apiOptions(t, api)
+	if _, err := LoadHolidays(context.Background(), 1404, opts); err != nil {
+		t.Fatal(err)
+	}
+	// The API now answers with a year without holidays.
+	api.holidays = nil
+	opts.Refresh = true
+	var writeErr error
+	opts.OnCacheWriteError = func(err error) { writeErr = err }
+	if _, err := LoadHolidays(context.Background(), 1404, opts); err != nil {
+		t.Fatal(err)
+	}
+	if writeErr == nil || !strings.Contains(writeErr.Error(), "refusing to replace") {
+		t.Errorf("cache write error = %v, want a refusal", writeErr)
+	}
+	cached, err := testStore(t, opts).read(1404)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cached["1404-04-15"]; !ok {
+		t.Errorf("the cached holidays were replaced: %v", cached)
+	}
+}
@@ -0,0 +1,147 @@
+// Package shamsy converts dates between the Shamsi (Solar Hijri) and
+// Gregorian calendars and answers holiday questions for Shamsi years.
+//
+//	cal, err := shamsy.LoadHolidays(ctx, 1404, shamsy.Options{})
+//	if err != nil {
+//		return err
+//	}
+//	if name, ok := cal.IsHoliday(shamsy.Date{Year: 1404, Month: 1, Day: 12}); ok {
+//		fmt.Println(name)
+//	}
+package shamsy
+
+import "time"
+
+// DateInfo is a date in either calendar together with its weekday name. It
+// is also the shape of the dates returned by the holiday API.
+type DateInfo struct {
+	Day     int    `json:"day"`
+	Month   int    `json:"month"`
+	Year    int    `json:"year"`
+	DayWeek string `json:"dayWeek"`
+}
+
+// shamsyWeekdays are the weekday names from Saturday, the first day of the
+// Shamsi week, to Friday.
+var shamsyWeekdays = []string{"Saturday", "Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday"}
+
+// goToShamsyWeekday maps a time.Weekday to its column in the Shamsi week.
+var goToShamsyWeekday = []int{1, 2, 3, 4, 5, 6, 0}
+
+// MinYear and MaxYear bound the Shamsi years the conversions support,
+// 1 Farvardin 1 (21 March 622) to 29 Esfand 3177 (20 March 3799). Leap years
+// follow the 33-year arithmetic rule, which matches the official calendar in
+// modern times; for the distant past and future the dates are proleptic and
+// may differ from the astronomical calendar by a day.
+const (
+	MinYear = 1
+	MaxYear = 3177
+)
+
+// ShamsyJDN returns the Julian Day Number of a Shamsi date. All conversions
+// go through it, so they agree with each other and with IsLeapYear.
+func ShamsyJDN(jy, jm, jd int) int {
+	y := jy + 1595
+	days := -355668 + 365*y + (y/33)*8 + ((y%33)+3)/4 + jd
+	if jm < 7 {
+		days += (jm - 1) * 31
+	} else {
+		days += (jm-7)*30 + 186
+	}
+	return days + 1721060
+}
+
+// IsLeapYear reports whether a Shamsi year has 30 days in Esfand.
+func IsLeapYear(year int) bool {
+	return ShamsyJDN(year+1, 1, 1)-ShamsyJDN(year, 1, 1) == 366
+}
+
+// InRange reports whether a Shamsi year is within MinYear and MaxYear.
+func InRange(year int) bool {
+	return year >= MinYear && year <= MaxYear
+}
+
+// GregorianInRange reports whether a Gregorian date converts to a Shamsi
+// date within MinYear and MaxYear.
+func GregorianInRange(gy, gm, gd int) bool {
+	j := GregorianJDN(gy, gm, gd)
+	return j >= ShamsyJDN(MinYear, 1, 1) && j < ShamsyJDN(MaxYear+1, 1, 1)
+}
+
+// MonthDays returns the number of days in a Shamsi month, or 0 for an
+// invalid month.
+func MonthDays(year, month int) int {
+	if month <= 6 {
+		return 31
+	} else if month <= 11 {
+		return 30
+	} else if month == 12 {
+		if IsLeapYear(year) {
+			return 30
+		}
+		return 29
+	}
+	return 0
+}
+
+// FromGregorian converts a Gregorian date to Shamsi year, month and day.
+func FromGregorian(gy, gm, gd int) (int, int, int) {
+	jy, jm, jd, _ := FromGregorianWithTrace(gy, gm, gd)
+	return jy, jm, jd
+}
+
+// ToGregorian converts a Shamsi date to Gregorian year, month and day.
+func ToGregorian(jy, jm, jd int) (int, int, int) {
+	return gregorianFromJDN(ShamsyJDN(jy, jm, jd))
+}
+
+// gregorianFromJDN converts a Julian Day Number to a proleptic Gregorian
+// date (Richards' algorithm).
+func gregorianFromJDN(j int) (int, int, int) {
+	a := j + 32044
+	b := (4*a + 3) / 146097
+	c := a - 146097*b/4
+	d := (4*c + 3) / 1461
+	e := c - 1461*d/4
+	m := (5*e + 2) / 153
+	day := e - (153*m+2)/5 + 1
+	month := m + 3 - 12*(m/10)
+	year := 100*b + d - 4800 + m/10
+	return year, month, day
+}
+
+// GregorianJDN returns the Julian Day Number of a proleptic Gregorian date.
+// It is a calendar-independent day count, handy for checking conversions.
+func GregorianJDN(gy, gm, gd int) int {
+	a := (14 - gm) / 12
+	y := gy + 4800 - a
+	m := gm + 12*a - 3
+	return gd + (153*m+2)/5 + 365*y + y/4 - y/100 + y/400 - 32045
+}
+
+// GregorianWeekday returns the day of the week of a proleptic Gregorian
+// date. It is computed from the day count alone, so unlike time.Date it
+// involves no time zone and never normalizes out-of-range years.
+func GregorianWeekday(gy, gm, gd int) time.Weekday {
+	// JDN 0 was a Monday.
+	return time.Weekday(((GregorianJDN(gy, gm, gd)+1)%7 + 7) % 7)
+}
+
+// WeekdayName returns the English weekday name of a Gregorian date.
+func WeekdayName(gy, gm, gd int) string {
+	return shamsyWeekdays[goToShamsyWeekday[int(GregorianWeekday(gy, gm, gd))]]
+}
+
+// GregorianToShamsyDate converts a Gregorian date to a Shamsi DateInfo with
+// DayWeek filled in.
+func GregorianToShamsyDate(gy, gm, gd int) DateInfo {
+	jy, jm, jd := FromGregorian(gy, gm, gd)
+	return DateInfo{Day: jd, Month: jm, Year: jy, DayWeek: WeekdayName(gy, gm, gd)}
+}
+
+// ShamsyToGregorianDate converts a Shamsi date to a Gregorian DateInfo with
+// DayWeek filled in.
+func ShamsyToGregorianDate(jy, jm, jd int) DateInfo {
+	gy, gm, gd := ToGregorian(jy, jm, jd)
+	return DateInfo{Day: gd, Month: gm, Year: gy, DayWeek: WeekdayName(gy, gm, gd)}
+}
@@ -0,0 +1,113 @@
+package shamsy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGregorianWeekday(t *testing.T) {
+	tests := []struct {
+		gy, gm, gd int
+		want       time.Weekday
+	}{
+		{2025, 3, 21, time.Friday},
+		{2025, 10, 16, time.Thursday},
+		{2000, 2, 29, time.Tuesday},
+		{1900, 3, 1, time.Thursday},
+		{622, 3, 21, time.Thursday},
+		{1, 1, 1, time.Monday},
+		// Years time.Date cannot normalize the same way.
+		{0, 12, 31, time.Sunday},
+		{-1, 1, 1, time.Friday},
+	}
+	for _, tt := range tests {
+		if got := GregorianWeekday(tt.gy, tt.gm, tt.gd); got != tt.want {
+			t.Errorf("GregorianWeekday(%d, %d, %d) = %v, want %v", tt.gy, tt.gm, tt.gd, got, tt.want)
+		}
+	}
+}
+
+func TestGregorianWeekdayMatchesTime(t *testing.T) {
+	end := time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+	for d := time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC); !d.After(end); d = d.Add(24 * time.Hour) {
+		if got := GregorianWeekday(d.Year(), int(d.Month()), d.Day()); got != d.Weekday() {
+			t.Fatalf("GregorianWeekday(%s) = %v, want %v", d.Format("2006-01-02"), got, d.Weekday())
+		}
+	}
+}
+
+func TestWeekdayName(t *testing.T) {
+	// Every weekday of the week of Nowruz 1404, from Friday 21 March 2025.
+	want := []string{"Friday", "Saturday", "Sunday", "Monday", "Tuesday", "Wednesday", "Thursday"}
+	for i, name := range want {
+		if got := WeekdayName(2025, 3, 21+i); got != name {
+			t.Errorf("WeekdayName(2025, 3, %d) = %q, want %q", 21+i, got, name)
+		}
+	}
+}
+
+func TestNowruz(t *testing.T) {
+	// Nowruz (1 Farvardin) of known years.
+	tests := []struct {
+		jy         int
+		gy, gm, gd int
+	}{
+		{1, 622, 3, 21},
+		{1300, 1921, 3, 21},
+		{1304, 1925, 3, 21},
+		{1357, 1978, 3, 21},
+		{1375, 1996, 3, 20},
+		{1399, 2020, 3, 20},
+		{1403, 2024, 3, 20},
+		{1404, 2025, 3, 21},
+	}
+	for _, tt := range tests {
+		if gy, gm, gd := ToGregorian(tt.jy, 1, 1); gy != tt.gy || gm != tt.gm || gd != tt.gd {
+			t.Errorf("ToGregorian(%d, 1, 1) = %d-%02d-%02d, want %d-%02d-%02d", tt.jy, gy, gm, gd, tt.gy, tt.gm, tt.gd)
+		}
+		if jy, jm, jd := FromGregorian(tt.gy, tt.gm, tt.gd); jy != tt.jy || jm != 1 || jd != 1 {
+			t.Errorf("FromGregorian(%d, %d, %d) = %d/%02d/%02d, want %d/01/01", tt.gy, tt.gm, tt.gd, jy, jm, jd, tt.jy)
+		}
+	}
+}
+
+func TestConversionRoundTrip(t *testing.T) {
+	want := ShamsyJDN(MinYear, 1, 1)
+	for jy := MinYear; jy <= MaxYear; jy++ {
+		for jm := 1; jm <= 12; jm++ {
+			for jd := 1; jd <= MonthDays(jy, jm); jd++ {
+				gy, gm, gd := ToGregorian(jy, jm, jd)
+				if GregorianJDN(gy, gm, gd) != want {
+					t.Fatalf("ToGregorian(%d, %d, %d) = %d-%02d-%02d is not the day after the previous one", jy, jm, jd, gy, gm, gd)
+				}
+				if y, m, d := FromGregorian(gy, gm, gd); y != jy || m != jm || d != jd {
+					t.Fatalf("FromGregorian(ToGregorian(%d, %d, %d)) = %d/%02d/%02d", jy, jm, jd, y, m, d)
+				}
+				want++
+			}
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	tests := []struct {
+		gy, gm, gd int
+		want       bool
+	}{
+		{622, 3, 20, false},
+		{622, 3, 21, true},
+		{2025, 10, 16, true},
+		{3799, 3, 20, true},
+		{3799, 3, 21, false},
+	}
+	for _, tt := range tests {
+		if got := GregorianInRange(tt.gy, tt.gm, tt.gd); got != tt.want {
+			t.Errorf("GregorianInRange(%d, %d, %d) = %v, want %v", tt.gy, tt.gm, tt.gd, got, tt.want)
+		}
+	}
+	for year, want := range map[int]bool{0: false, MinYear: true, 1404: true, MaxYear: true, MaxYear + 1: false} {
+		if got := InRange(year); got != want {
+			t.Errorf("InRange(%d) = %v, want %v", year, got, want)
+		}
+	}
+}
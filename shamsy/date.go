@@ -0,0 +1,86 @@
+package shamsy
+
+import (
+	"fmt"
+	"time"
+)
+
+// Date is a day of the Shamsi calendar.
+type Date struct {
+	Year, Month, Day int
+}
+
+// DateFromGregorian returns the Shamsi Date of a Gregorian date.
+func DateFromGregorian(gy, gm, gd int) Date {
+	jy, jm, jd := FromGregorian(gy, gm, gd)
+	return Date{Year: jy, Month: jm, Day: jd}
+}
+
+// Today returns the current Shamsi date in the local time zone.
+func Today() Date {
+	now := time.Now()
+	return DateFromGregorian(now.Year(), int(now.Month()), now.Day())
+}
+
+// String formats d as YYYY/MM/DD.
+func (d Date) String() string {
+	return fmt.Sprintf("%04d/%02d/%02d", d.Year, d.Month, d.Day)
+}
+
+// key is the representation of d in holiday caches.
+func (d Date) key() string {
+	return fmt.Sprintf("%d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// Valid reports whether d names an existing Shamsi day.
+func (d Date) Valid() bool {
+	return d.Year >= 1 && d.Month >= 1 && d.Month <= 12 && d.Day >= 1 && d.Day <= MonthDays(d.Year, d.Month)
+}
+
+// Compare returns -1, 0 or +1 depending on whether d is before, equal to or
+// after other.
+func (d Date) Compare(other Date) int {
+	a := d.Year*10000 + d.Month*100 + d.Day
+	b := other.Year*10000 + other.Month*100 + other.Day
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Next returns the day after d.
+func (d Date) Next() Date {
+	if d.Day < MonthDays(d.Year, d.Month) {
+		return Date{Year: d.Year, Month: d.Month, Day: d.Day + 1}
+	}
+	if d.Month < 12 {
+		return Date{Year: d.Year, Month: d.Month + 1, Day: 1}
+	}
+	return Date{Year: d.Year + 1, Month: 1, Day: 1}
+}
+
+// EpochDays returns the number of days from 1 Farvardin 1 to d, so that
+// 1 Farvardin 1 is day 0. It increases by one every day and sorts like the
+// dates themselves.
+func (d Date) EpochDays() int {
+	return ShamsyJDN(d.Year, d.Month, d.Day) - ShamsyJDN(MinYear, 1, 1)
+}
+
+// DateFromEpochDays returns the Date that is n days after 1 Farvardin 1; it
+// is the inverse of EpochDays.
+func DateFromEpochDays(n int) Date {
+	return DateFromGregorian(gregorianFromJDN(ShamsyJDN(MinYear, 1, 1) + n))
+}
+
+// Gregorian returns the Gregorian equivalent of d.
+func (d Date) Gregorian() DateInfo {
+	return ShamsyToGregorianDate(d.Year, d.Month, d.Day)
+}
+
+// Weekday returns the day of the week d falls on.
+func (d Date) Weekday() time.Weekday {
+	return GregorianWeekday(ToGregorian(d.Year, d.Month, d.Day))
+}
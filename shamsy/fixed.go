@@ -0,0 +1,58 @@
+package shamsy
+
+import (
+	"context"
+	"fmt"
+)
+
+// FixedHoliday is an official holiday that falls on the same Shamsi date
+// every year.
+type FixedHoliday struct {
+	Month int    `json:"month"`
+	Day   int    `json:"day"`
+	Name  string `json:"name"`
+}
+
+// fixedHolidays are the national holidays with a fixed Shamsi date. The names
+// match the ones used by the pnldev.com API so that translations apply.
+var fixedHolidays = []FixedHoliday{
+	{1, 1, "جشن نوروز"},
+	{1, 2, "عید نوروز"},
+	{1, 3, "عید نوروز"},
+	{1, 4, "عید نوروز"},
+	{1, 12, "روز جمهوری اسلامی"},
+	{1, 13, "روز طبیعت"},
+	{3, 14, "رحلت امام خمینی"},
+	{3, 15, "قیام ۱۵ خرداد"},
+	{11, 22, "پیروزی انقلاب اسلامی"},
+	{12, 29, "روز ملی شدن صنعت نفت"},
+}
+
+// FixedProvider supplies only the holidays with a fixed Shamsi date from a
+// built-in table. Religious holidays follow the lunar Hijri calendar and move
+// every year, so they are never included. It needs no network access.
+type FixedProvider struct {
+	// Table replaces the built-in table when not nil, e.g. with one from a
+	// newer data bundle. A date missing from a year, such as Esfand 30 in a
+	// common year, is skipped in that year.
+	Table []FixedHoliday
+}
+
+// Holidays implements Provider.
+func (p FixedProvider) Holidays(ctx context.Context, year int) ([]Holiday, error) {
+	if !InRange(year) {
+		return nil, fmt.Errorf("year %d is outside the supported range %d-%d", year, MinYear, MaxYear)
+	}
+	table := p.Table
+	if table == nil {
+		table = fixedHolidays
+	}
+	holidays := make([]Holiday, 0, len(table))
+	for _, f := range table {
+		if f.Month < 1 || f.Month > 12 || f.Day < 1 || f.Day > MonthDays(year, f.Month) {
+			continue
+		}
+		holidays = append(holidays, Holiday{Date: Date{Year: year, Month: f.Month, Day: f.Day}, Name: f.Name})
+	}
+	return holidays, nil
+}
@@ -0,0 +1,81 @@
+package shamsy
+
+import (
+	"strconv"
+	"strings"
+)
+
+// shamsyMonthNames are the Shamsi month names, Farvardin first.
+var shamsyMonthNames = []string{
+	"Farvardin", "Ordibehesht", "Khordad", "Tir", "Mordad", "Shahrivar",
+	"Mehr", "Aban", "Azar", "Dey", "Bahman", "Esfand",
+}
+
+// gregorianMonthNames are the Gregorian month names, January first.
+var gregorianMonthNames = []string{
+	"January", "February", "March", "April", "May", "June",
+	"July", "August", "September", "October", "November", "December",
+}
+
+// layoutTokens are the tokens of a date layout, longest first so that
+// "MonthName" is not read as "M" and "DD" not as two "D"s.
+var layoutTokens = []string{"MonthName", "Weekday", "YYYY", "MM", "DD", "M", "D"}
+
+// FormatShamsi formats a Shamsi date according to layout, in which these
+// tokens are replaced and any other text is copied:
+//
+//	YYYY       year, at least four digits
+//	MM, M      month with and without a leading zero
+//	DD, D      day with and without a leading zero
+//	MonthName  month name, e.g. Farvardin
+//	Weekday    weekday name, e.g. Saturday
+//
+// For example "YYYY/MM/DD" gives 1404/01/05 and "D MonthName YYYY" gives
+// 5 Farvardin 1404. The month must be between 1 and 12.
+func FormatShamsi(jy, jm, jd int, layout string) string {
+	gy, gm, gd := ToGregorian(jy, jm, jd)
+	return formatDate(layout, jy, jm, jd, shamsyMonthNames[jm-1], WeekdayName(gy, gm, gd))
+}
+
+// FormatGregorian is FormatShamsi for a Gregorian date: "MonthName D, YYYY"
+// gives March 25, 2025.
+func FormatGregorian(gy, gm, gd int, layout string) string {
+	return formatDate(layout, gy, gm, gd, gregorianMonthNames[gm-1], WeekdayName(gy, gm, gd))
+}
+
+// formatDate replaces the layout tokens with the given date fields.
+func formatDate(layout string, y, m, d int, monthName, weekday string) string {
+	pad := func(n, width int) string {
+		s := strconv.Itoa(n)
+		if len(s) < width {
+			s = strings.Repeat("0", width-len(s)) + s
+		}
+		return s
+	}
+	values := map[string]string{
+		"MonthName": monthName,
+		"Weekday":   weekday,
+		"YYYY":      pad(y, 4),
+		"MM":        pad(m, 2),
+		"DD":        pad(d, 2),
+		"M":         strconv.Itoa(m),
+		"D":         strconv.Itoa(d),
+	}
+	var b strings.Builder
+	for i := 0; i < len(layout); {
+		matched := false
+		for _, token := range layoutTokens {
+			if strings.HasPrefix(layout[i:], token) {
+				b.WriteString(values[token])
+				i += len(token)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			b.WriteByte(layout[i])
+			i++
+		}
+	}
+	return b.String()
+}
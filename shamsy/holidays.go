@@ -0,0 +1,355 @@
+package shamsy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Holiday is an official holiday on a Shamsi date.
+type Holiday struct {
+	Date Date
+	Name string
+	// Gregorian is the Gregorian date of the holiday. Providers may set it to
+	// their own mapping, which then takes precedence in IsGregorianHoliday;
+	// holidays returned by a HolidayCalendar carry the converted date.
+	Gregorian DateInfo
+}
+
+// Options controls how LoadHolidays finds the holidays of a year.
+type Options struct {
+	// CacheDir holds one JSON file per year; empty means DefaultCacheDir.
+	CacheDir string
+	// Providers are asked in order on a cache miss until one succeeds;
+	// nil means the providers of Region.
+	Providers []Provider
+	// Region selects the holiday set, e.g. "ir"; empty means DefaultRegion.
+	// Each region other than DefaultRegion is cached in its own
+	// subdirectory of CacheDir.
+	Region string
+	// Offline restricts loading to the cache.
+	Offline bool
+	// Observed also marks the next working day of holidays falling on a
+	// Friday, as some employers do.
+	Observed bool
+	// OnCacheWriteError, if set, is told when a fetched year could not be
+	// cached. Loading still succeeds unless StrictCache is set.
+	OnCacheWriteError func(err error)
+	// StrictCache makes a failure to cache a fetched year an error.
+	StrictCache bool
+	// NoCache neither reads nor writes the cache; every load asks the
+	// providers.
+	NoCache bool
+	// Refresh skips reading the cache and asks the providers, then caches
+	// their answer as usual.
+	Refresh bool
+	// NoCacheWrite reads the cache but never writes fetched holidays to it.
+	NoCacheWrite bool
+	// Progress, if set, is told when fetching from the providers starts
+	// (StageFetch, once per provider asked) and ends (StageDone). It lets
+	// callers show a spinner or a status line while waiting.
+	Progress func(stage string)
+}
+
+// Stages reported to Options.Progress.
+const (
+	StageFetch = "fetch"
+	StageDone  = "done"
+)
+
+// HolidayCalendar answers holiday questions for the Shamsi years it was
+// loaded for. It is never modified after construction, so it is safe for
+// concurrent use. The zero value and nil have no holidays.
+type HolidayCalendar struct {
+	// entries uses the cache format: "1404-01-12" keys for Shamsi dates and
+	// "g:2025-04-01" keys for the provider's Gregorian dates.
+	entries map[string]string
+	// gregorianYears are the Shamsi years whose entries include Gregorian
+	// keys. Caches written before those keys existed lack them.
+	gregorianYears map[int]bool
+}
+
+// newHolidayCalendar returns a calendar of entries in the cache format.
+func newHolidayCalendar(entries map[string]string) *HolidayCalendar {
+	c := &HolidayCalendar{entries: entries, gregorianYears: make(map[int]bool)}
+	for k := range entries {
+		if strings.HasPrefix(k, "g:") {
+			c.gregorianYears[keyMonth(k).Year] = true
+		}
+	}
+	return c
+}
+
+// add marks d as a holiday. In years with Gregorian keys the Gregorian date
+// of d is added too, unless the provider already named that day.
+func (c *HolidayCalendar) add(d Date, name string) {
+	c.entries[d.key()] = name
+	if c.gregorianYears[d.Year] {
+		g := d.Gregorian()
+		if _, ok := c.entries[gregorianKey(g.Year, g.Month, g.Day)]; !ok {
+			c.entries[gregorianKey(g.Year, g.Month, g.Day)] = name
+		}
+	}
+}
+
+// LoadHolidays loads the holidays of a Shamsi year from the cache, asking the
+// providers and caching their answer on a miss.
+func LoadHolidays(ctx context.Context, year int, opts Options) (*HolidayCalendar, error) {
+	return loadCalendar(ctx, year, 0, opts)
+}
+
+// LoadMonthHolidays is like LoadHolidays for callers that only need one
+// month. On a cache miss a MonthProvider is asked for just that month, which
+// is then cached on its own; the calendar may know nothing about the other
+// months of the year in that case.
+func LoadMonthHolidays(ctx context.Context, year, month int, opts Options) (*HolidayCalendar, error) {
+	return loadCalendar(ctx, year, month, opts)
+}
+
+func loadCalendar(ctx context.Context, year, month int, opts Options) (*HolidayCalendar, error) {
+	entries, err := loadEntries(ctx, year, month, opts)
+	if err != nil {
+		return nil, err
+	}
+	cal := newHolidayCalendar(entries)
+	if opts.Observed {
+		cal = cal.withObserved()
+	}
+	return cal, nil
+}
+
+// loadEntries loads the entries of a year, or with month > 0 at least those
+// of that month.
+func loadEntries(ctx context.Context, year, month int, opts Options) (map[string]string, error) {
+	store, err := opts.store()
+	if err != nil {
+		return nil, err
+	}
+	if !opts.NoCache && !opts.Refresh {
+		read := store.read
+		if month > 0 {
+			read = func(year int) (map[string]string, error) { return store.readMonth(year, month) }
+		}
+		if cached, err := read(year); err == nil {
+			return cached, nil
+		}
+	}
+	if opts.Offline {
+		return nil, fmt.Errorf("holidays of %d are not cached and loading is offline", year)
+	}
+	holidays, byMonth, err := fetch(ctx, year, month, opts)
+	if err != nil {
+		return nil, err
+	}
+	entries := make(map[string]string)
+	for _, h := range holidays {
+		entries[h.Date.key()] = h.Name
+		if g := h.Gregorian; g.Year > 0 {
+			entries[gregorianKey(g.Year, g.Month, g.Day)] = h.Name
+		}
+	}
+	if opts.NoCache || opts.NoCacheWrite {
+		return entries, nil
+	}
+	write := store.write
+	if byMonth {
+		write = func(year int, entries map[string]string) error { return store.writeMonth(year, month, entries) }
+	}
+	if err := write(year, entries); err != nil {
+		if opts.StrictCache {
+			return nil, fmt.Errorf("failed to cache holidays of %d: %v", year, err)
+		}
+		if opts.OnCacheWriteError != nil {
+			opts.OnCacheWriteError(err)
+		}
+	}
+	return entries, nil
+}
+
+// FetchHolidays asks the providers of opts in order for the holidays of a
+// Shamsi year until one succeeds, bypassing the cache. Progress is reported
+// to opts.Progress and ctx cancels a fetch in flight.
+func FetchHolidays(ctx context.Context, year int, opts Options) ([]Holiday, error) {
+	holidays, _, err := fetch(ctx, year, 0, opts)
+	return holidays, err
+}
+
+// fetch asks the providers for a year or, with month > 0, preferably just
+// that month. byMonth reports whether only the month was fetched.
+func fetch(ctx context.Context, year, month int, opts Options) (holidays []Holiday, byMonth bool, err error) {
+	if opts.Progress != nil {
+		defer opts.Progress(StageDone)
+	}
+	providers := opts.Providers
+	if providers == nil {
+		if providers, err = RegionProviders(opts.Region); err != nil {
+			return nil, false, err
+		}
+	}
+	for _, p := range providers {
+		if err := ctx.Err(); err != nil {
+			return nil, false, err
+		}
+		if opts.Progress != nil {
+			opts.Progress(StageFetch)
+		}
+		mp, ok := p.(MonthProvider)
+		if byMonth = ok && month > 0; byMonth {
+			holidays, err = mp.MonthHolidays(ctx, year, month)
+		} else {
+			holidays, err = p.Holidays(ctx, year)
+		}
+		if err == nil {
+			return holidays, byMonth, nil
+		}
+	}
+	if err == nil {
+		err = fmt.Errorf("no holiday providers configured")
+	}
+	return nil, false, err
+}
+
+// Merge returns a calendar with the holidays of all cals; nil calendars are
+// skipped. Later calendars win on conflicting dates.
+func Merge(cals ...*HolidayCalendar) *HolidayCalendar {
+	entries := make(map[string]string)
+	for _, c := range cals {
+		if c == nil {
+			continue
+		}
+		for k, v := range c.entries {
+			entries[k] = v
+		}
+	}
+	return newHolidayCalendar(entries)
+}
+
+// WithHolidays returns a copy of c with the extra holidays added. Dates that
+// are already holidays keep their name, so no day is counted twice.
+func (c *HolidayCalendar) WithHolidays(extra []Holiday) *HolidayCalendar {
+	result := Merge(c)
+	for _, h := range extra {
+		if _, ok := result.entries[h.Date.key()]; !ok {
+			result.add(h.Date, h.Name)
+		}
+	}
+	return result
+}
+
+// IsHoliday returns the name of the holiday on d.
+func (c *HolidayCalendar) IsHoliday(d Date) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	name, ok := c.entries[d.key()]
+	return name, ok
+}
+
+// IsGregorianHoliday returns the name of the holiday on a Gregorian date. The
+// provider's own Gregorian mapping is used, so that the official calendar
+// wins wherever it differs from the arithmetic conversion. Only years cached
+// without Gregorian keys fall back to the converted Shamsi date; otherwise a
+// holiday the two disagree on would be colored on both days.
+func (c *HolidayCalendar) IsGregorianHoliday(gy, gm, gd int) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	if name, ok := c.entries[gregorianKey(gy, gm, gd)]; ok {
+		return name, true
+	}
+	d := DateFromGregorian(gy, gm, gd)
+	if c.gregorianYears[d.Year] {
+		return "", false
+	}
+	return c.IsHoliday(d)
+}
+
+// IsWorkingDay reports whether d is neither a Friday nor a holiday.
+func (c *HolidayCalendar) IsWorkingDay(d Date) bool {
+	if _, ok := c.IsHoliday(d); ok {
+		return false
+	}
+	return d.Weekday() != time.Friday
+}
+
+// Holidays returns every loaded holiday sorted by date.
+func (c *HolidayCalendar) Holidays() []Holiday {
+	if c == nil {
+		return nil
+	}
+	var holidays []Holiday
+	for key, name := range c.entries {
+		var d Date
+		// Gregorian-keyed duplicates ("g:...") do not match this format.
+		if _, err := fmt.Sscanf(key, "%d-%d-%d", &d.Year, &d.Month, &d.Day); err != nil {
+			continue
+		}
+		holidays = append(holidays, Holiday{Date: d, Name: name, Gregorian: d.Gregorian()})
+	}
+	sort.Slice(holidays, func(i, j int) bool {
+		return holidays[i].Date.Compare(holidays[j].Date) < 0
+	})
+	return holidays
+}
+
+// Between returns the holidays from from to to inclusive, sorted by date.
+func (c *HolidayCalendar) Between(from, to Date) []Holiday {
+	var holidays []Holiday
+	for _, h := range c.Holidays() {
+		if h.Date.Compare(from) >= 0 && h.Date.Compare(to) <= 0 {
+			holidays = append(holidays, h)
+		}
+	}
+	return holidays
+}
+
+// HolidaysIn returns the holidays of a Shamsi month sorted by date.
+func (c *HolidayCalendar) HolidaysIn(year, month int) []Holiday {
+	return c.Between(Date{Year: year, Month: month, Day: 1}, Date{Year: year, Month: month, Day: MonthDays(year, month)})
+}
+
+// NextHoliday returns the first loaded holiday after the given date.
+func (c *HolidayCalendar) NextHoliday(after Date) (Holiday, bool) {
+	for _, h := range c.Holidays() {
+		if h.Date.Compare(after) > 0 {
+			return h, true
+		}
+	}
+	return Holiday{}, false
+}
+
+// WorkingDays counts the working days from from to to inclusive.
+func (c *HolidayCalendar) WorkingDays(from, to Date) int {
+	n := 0
+	for d := from; d.Compare(to) <= 0; d = d.Next() {
+		if c.IsWorkingDay(d) {
+			n++
+		}
+	}
+	return n
+}
+
+// withObserved returns a copy of c in which every holiday that falls on a
+// Friday also marks the next day that is neither a Friday, a holiday nor
+// already an observed day. Holidays are processed in date order so that runs
+// of consecutive holidays push their observed days past each other.
+func (c *HolidayCalendar) withObserved() *HolidayCalendar {
+	result := Merge(c)
+	for _, h := range c.Holidays() {
+		if h.Date.Weekday() != time.Friday {
+			continue
+		}
+		od := h.Date.Next()
+		for {
+			_, taken := result.entries[od.key()]
+			if !taken && od.Weekday() != time.Friday {
+				break
+			}
+			od = od.Next()
+		}
+		result.add(od, h.Name+" (observed)")
+	}
+	return result
+}
@@ -0,0 +1,37 @@
+package shamsy
+
+import "testing"
+
+func TestIsGregorianHoliday(t *testing.T) {
+	ashura := Date{Year: 1404, Month: 4, Day: 15}
+	g := ashura.Gregorian()
+	// The provider maps the holiday to the day after the converted date.
+	next := Date{Year: 1404, Month: 4, Day: 16}.Gregorian()
+	withKeys := newHolidayCalendar(map[string]string{
+		ashura.key(): "Ashura",
+		gregorianKey(next.Year, next.Month, next.Day): "Ashura",
+	})
+	withoutKeys := newHolidayCalendar(map[string]string{ashura.key(): "Ashura"})
+	rule := Date{Year: 1404, Month: 5, Day: 1}
+	rg := rule.Gregorian()
+
+	tests := []struct {
+		name       string
+		cal        *HolidayCalendar
+		gy, gm, gd int
+		want       bool
+	}{
+		{"provider date", withKeys, next.Year, next.Month, next.Day, true},
+		{"converted date with Gregorian keys", withKeys, g.Year, g.Month, g.Day, false},
+		{"converted date without Gregorian keys", withoutKeys, g.Year, g.Month, g.Day, true},
+		{"day after without Gregorian keys", withoutKeys, next.Year, next.Month, next.Day, false},
+		{"added holiday", withKeys.WithHolidays([]Holiday{{Date: rule, Name: "Rule"}}), rg.Year, rg.Month, rg.Day, true},
+		{"merged", Merge(withoutKeys, withKeys), g.Year, g.Month, g.Day, false},
+		{"nil calendar", nil, g.Year, g.Month, g.Day, false},
+	}
+	for _, tt := range tests {
+		if _, got := tt.cal.IsGregorianHoliday(tt.gy, tt.gm, tt.gd); got != tt.want {
+			t.Errorf("%s: IsGregorianHoliday(%d-%02d-%02d) = %v, want %v", tt.name, tt.gy, tt.gm, tt.gd, got, tt.want)
+		}
+	}
+}
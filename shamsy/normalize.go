@@ -0,0 +1,31 @@
+package shamsy
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NormalizeName folds the spelling differences seen in Persian holiday and
+// event names so that equal names compare equal: Arabic yeh (ي, ى) and kaf
+// (ك) become their Persian forms, tatweel (ـ), zero-width non-joiners and
+// Arabic diacritics such as fatha or tanwin are removed, and whitespace is
+// collapsed to single spaces. Apply it to both sides of a comparison.
+func NormalizeName(name string) string {
+	var b strings.Builder
+	b.Grow(len(name))
+	for _, r := range name {
+		switch {
+		case r == 'ي' || r == 'ى':
+			b.WriteRune('ی')
+		case r == 'ك':
+			b.WriteRune('ک')
+		case r == 'ـ', r == '‌':
+		case unicode.Is(unicode.Mn, r):
+			// Combining marks: the Arabic harakat, shadda, sukun and
+			// superscript alef.
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
@@ -0,0 +1,27 @@
+package shamsy
+
+import "testing"
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"unchanged", "عید نوروز", "عید نوروز"},
+		{"arabic yeh", "عيد نوروز", "عید نوروز"},
+		{"alef maksura", "عيسى", "عیسی"},
+		{"arabic kaf", "كريسمس", "کریسمس"},
+		{"tatweel", "عـــید", "عید"},
+		{"zwnj", "می\u200cروم", "میروم"},
+		{"fatha and tanwin", "عيد\u064e سعيد\u064b", "عید سعید"},
+		{"shadda and sukun", "محم\u0651د\u0652", "محمد"},
+		{"superscript alef", "رحم\u0670ن", "رحمن"},
+		{"whitespace", "  عید \t سعید\n فطر ", "عید سعید فطر"},
+		{"latin", "Nowruz  holiday", "Nowruz holiday"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeName(tt.in); got != tt.want {
+			t.Errorf("%s: NormalizeName(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package shamsy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Provider supplies the official holidays of a Shamsi year.
+type Provider interface {
+	Holidays(ctx context.Context, year int) ([]Holiday, error)
+}
+
+// MonthProvider is a Provider that can also supply the holidays of a single
+// month, which is cheaper when only one month is needed.
+type MonthProvider interface {
+	Provider
+	MonthHolidays(ctx context.Context, year, month int) ([]Holiday, error)
+}
+
+// CalendarResponse is the response of the pnldev.com calendar API.
+type CalendarResponse struct {
+	Status bool                 `json:"status"`
+	Result map[string]MonthData `json:"result"`
+}
+
+// MonthData holds the days of one month of a CalendarResponse.
+type MonthData map[string]DayData
+
+// DayData is one day of a CalendarResponse.
+type DayData struct {
+	Solar     DateInfo `json:"solar"`
+	Gregorian DateInfo `json:"gregorian"`
+	Holiday   bool     `json:"holiday"`
+	Event     []string `json:"event"`
+}
+
+// DefaultAPIURL is the endpoint of the pnldev.com calendar API.
+const DefaultAPIURL = "https://pnldev.com/api/calender"
+
+// APIProvider fetches holidays from the pnldev.com calendar API.
+type APIProvider struct {
+	// Client is used for the request; nil means http.DefaultClient.
+	Client *http.Client
+	// URL is the API endpoint; empty means DefaultAPIURL. Servers that
+	// mimic the API's response shape, such as test fakes, can be used too.
+	URL string
+	// OnCheck, if set, is told the SchemaCheck of every accepted response,
+	// so that callers can report a response format that may have changed.
+	OnCheck func(check SchemaCheck)
+}
+
+// Holidays implements Provider.
+func (p APIProvider) Holidays(ctx context.Context, year int) ([]Holiday, error) {
+	return p.fetch(ctx, year, 0)
+}
+
+// MonthHolidays implements MonthProvider using the API's month parameter.
+func (p APIProvider) MonthHolidays(ctx context.Context, year, month int) ([]Holiday, error) {
+	all, err := p.fetch(ctx, year, month)
+	if err != nil {
+		return nil, err
+	}
+	// Servers that ignore the month parameter answer with the whole year.
+	var holidays []Holiday
+	for _, h := range all {
+		if h.Date.Year == year && h.Date.Month == month {
+			holidays = append(holidays, h)
+		}
+	}
+	return holidays, nil
+}
+
+// fetch requests the holidays of a year, or of one month when month > 0,
+// from the API.
+func (p APIProvider) fetch(ctx context.Context, year, month int) ([]Holiday, error) {
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	base := p.URL
+	if base == "" {
+		base = DefaultAPIURL
+	}
+	query := fmt.Sprintf("year=%d&holiday=true", year)
+	if month > 0 {
+		query = fmt.Sprintf("year=%d&month=%d&holiday=true", year, month)
+	}
+	url := fmt.Sprintf("%s?%s", base, query)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch holidays: %v", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch holidays: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+	var calendar CalendarResponse
+	if err := json.Unmarshal(body, &calendar); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %v", err)
+	}
+	if err := validateResponse(calendar, year, month); err != nil {
+		return nil, err
+	}
+	var holidays []Holiday
+	for _, days := range calendar.Result {
+		for _, dayData := range days {
+			if !dayData.Holiday {
+				continue
+			}
+			name := "Holiday"
+			if events := uniqueEvents(dayData.Event); len(events) > 0 {
+				name = strings.Join(events, "; ")
+			}
+			holidays = append(holidays, Holiday{
+				Date:      Date{Year: dayData.Solar.Year, Month: dayData.Solar.Month, Day: dayData.Solar.Day},
+				Name:      name,
+				Gregorian: dayData.Gregorian,
+			})
+		}
+	}
+	if p.OnCheck != nil {
+		p.OnCheck(checkSchema(body, year, month, calendar, holidays))
+	}
+	return holidays, nil
+}
+
+// uniqueEvents drops repeated and blank event names, keeping the first
+// occurrence of each in order. The API sometimes lists an event twice for a
+// day, which would otherwise read "Nowruz; Nowruz".
+func uniqueEvents(events []string) []string {
+	seen := make(map[string]bool, len(events))
+	unique := make([]string, 0, len(events))
+	for _, e := range events {
+		e = strings.TrimSpace(e)
+		if e == "" || seen[e] {
+			continue
+		}
+		seen[e] = true
+		unique = append(unique, e)
+	}
+	return unique
+}
+
+// minYearDays is the fewest days a full-year response may contain. The API
+// lists every day of the year, holiday or not; far fewer means the response
+// is truncated or its shape changed.
+const minYearDays = 300
+
+// validateResponse checks a decoded response before it is trusted, so that a
+// changed or broken API fails loudly instead of caching an empty or garbled
+// set of holidays. month is 0 for a full-year query.
+func validateResponse(calendar CalendarResponse, year, month int) error {
+	if !calendar.Status {
+		return fmt.Errorf("unexpected API response: status is false")
+	}
+	days := 0
+	for _, monthData := range calendar.Result {
+		for _, dayData := range monthData {
+			days++
+			s := dayData.Solar
+			if s.Year != year {
+				return fmt.Errorf("unexpected API response: got %d/%02d/%02d for year %d", s.Year, s.Month, s.Day, year)
+			}
+			if s.Month < 1 || s.Month > 12 || s.Day < 1 || s.Day > MonthDays(s.Year, s.Month) {
+				return fmt.Errorf("unexpected API response: invalid date %d/%02d/%02d", s.Year, s.Month, s.Day)
+			}
+		}
+	}
+	if month == 0 && days < minYearDays {
+		return fmt.Errorf("unexpected API response: %d days for year %d, expected at least %d", days, year, minYearDays)
+	}
+	return nil
+}
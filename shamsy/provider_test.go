@@ -0,0 +1,297 @@
+package shamsy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeAPI is an httptest server mimicking the pnldev.com calendar API. It
+// serves the days of the queried year, or month with the month parameter,
+// marking the dates in holidays. Setting status, body or code replaces the
+// normal answer, and delay holds every response back.
+type fakeAPI struct {
+	*httptest.Server
+	holidays map[Date]string
+	requests atomic.Int32
+
+	status      bool
+	body        string
+	code        int
+	delay       time.Duration
+	ignoreMonth bool
+}
+
+func newFakeAPI(t *testing.T, holidays map[Date]string) *fakeAPI {
+	t.Helper()
+	f := &fakeAPI{holidays: holidays, status: true}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeAPI) serve(w http.ResponseWriter, r *http.Request) {
+	f.requests.Add(1)
+	if f.delay > 0 {
+		select {
+		case <-time.After(f.delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if f.code != 0 {
+		http.Error(w, http.StatusText(f.code), f.code)
+		return
+	}
+	if f.body != "" {
+		fmt.Fprint(w, f.body)
+		return
+	}
+	year, err := strconv.Atoi(r.URL.Query().Get("year"))
+	if err != nil {
+		http.Error(w, "bad year", http.StatusBadRequest)
+		return
+	}
+	month, _ := strconv.Atoi(r.URL.Query().Get("month"))
+	if f.ignoreMonth {
+		month = 0
+	}
+	json.NewEncoder(w).Encode(f.response(year, month))
+}
+
+// response builds the answer for a year, or a single month when month > 0.
+func (f *fakeAPI) response(year, month int) CalendarResponse {
+	resp := CalendarResponse{Status: f.status, Result: map[string]MonthData{}}
+	for m := 1; m <= 12; m++ {
+		if month > 0 && m != month {
+			continue
+		}
+		days := MonthData{}
+		for d := 1; d <= MonthDays(year, m); d++ {
+			date := Date{Year: year, Month: m, Day: d}
+			day := DayData{
+				Solar:     DateInfo{Year: year, Month: m, Day: d},
+				Gregorian: date.Gregorian(),
+			}
+			if name, ok := f.holidays[date]; ok {
+				day.Holiday = true
+				day.Event = []string{name}
+			}
+			days[strconv.Itoa(d)] = day
+		}
+		resp.Result[strconv.Itoa(m)] = days
+	}
+	return resp
+}
+
+// testHolidays are the holidays the fake API serves in most tests.
+var testHolidays = map[Date]string{
+	{Year: 1403, Month: 1, Day: 1}:  "Nowruz",
+	{Year: 1403, Month: 4, Day: 25}: "Ashura",
+	{Year: 1404, Month: 1, Day: 1}:  "Nowruz",
+	{Year: 1404, Month: 4, Day: 15}: "Ashura",
+}
+
+func TestAPIProviderHolidays(t *testing.T) {
+	api := newFakeAPI(t, testHolidays)
+	var checks []SchemaCheck
+	p := APIProvider{URL: api.URL, OnCheck: func(c SchemaCheck) { checks = append(checks, c) }}
+	holidays, err := p.Holidays(context.Background(), 1404)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(holidays) != 2 {
+		t.Fatalf("got %d holidays, want 2: %v", len(holidays), holidays)
+	}
+	for _, h := range holidays {
+		if testHolidays[h.Date] != h.Name {
+			t.Errorf("unexpected holiday %v %q", h.Date, h.Name)
+		}
+		if h.Gregorian != h.Date.Gregorian() {
+			t.Errorf("%v: Gregorian = %v, want %v", h.Date, h.Gregorian, h.Date.Gregorian())
+		}
+	}
+	if len(checks) != 1 || !checks[0].OK() {
+		t.Errorf("schema checks = %v, want one passing check", checks)
+	}
+}
+
+func TestAPIProviderMonthHolidays(t *testing.T) {
+	for _, ignoreMonth := range []bool{false, true} {
+		api := newFakeAPI(t, testHolidays)
+		api.ignoreMonth = ignoreMonth
+		holidays, err := APIProvider{URL: api.URL}.MonthHolidays(context.Background(), 1404, 4)
+		if err != nil {
+			t.Fatalf("ignoreMonth=%v: %v", ignoreMonth, err)
+		}
+		if len(holidays) != 1 || holidays[0].Date != (Date{Year: 1404, Month: 4, Day: 15}) {
+			t.Errorf("ignoreMonth=%v: got %v, want only Ashura on 1404/04/15", ignoreMonth, holidays)
+		}
+	}
+}
+
+func TestAPIProviderErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(*fakeAPI)
+		want  string
+	}{
+		{"status false", func(f *fakeAPI) { f.status = false }, "status is false"},
+		{"malformed JSON", func(f *fakeAPI) { f.body = `{"status": true, "result": {` }, "failed to parse JSON"},
+		{"server error", func(f *fakeAPI) { f.code = http.StatusInternalServerError }, "unexpected status code: 500"},
+		{"truncated year", func(f *fakeAPI) { f.body = `{"status": true, "result": {}}` }, "0 days for year 1404"},
+		{"wrong year", func(f *fakeAPI) {
+			data, _ := json.Marshal(f.response(1403, 0))
+			f.body = string(data)
+		}, "for year 1404"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newFakeAPI(t, testHolidays)
+			tt.setup(api)
+			_, err := APIProvider{URL: api.URL}.Holidays(context.Background(), 1404)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIProviderSlowResponse(t *testing.T) {
+	api := newFakeAPI(t, testHolidays)
+	api.delay = 10 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := APIProvider{URL: api.URL}.Holidays(ctx, 1404)
+	if err == nil || !strings.Contains(err.Error(), "deadline exceeded") {
+		t.Errorf("error = %v, want a deadline exceeded error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("the request took %v; the context did not cancel it", elapsed)
+	}
+}
+
+func TestUniqueEvents(t *testing.T) {
+	tests := []struct {
+		in   []string
+		want []string
+	}{
+		{nil, []string{}},
+		{[]string{"Nowruz"}, []string{"Nowruz"}},
+		{[]string{"Nowruz", "Nowruz"}, []string{"Nowruz"}},
+		{[]string{"Nowruz", " Nowruz ", "", "Spring"}, []string{"Nowruz", "Spring"}},
+		{[]string{"B", "A", "B", "A"}, []string{"B", "A"}},
+	}
+	for _, tt := range tests {
+		got := uniqueEvents(tt.in)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+			t.Errorf("uniqueEvents(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestAPIProviderDuplicateEvents(t *testing.T) {
+	api := newFakeAPI(t, testHolidays)
+	resp := api.response(1404, 0)
+	day := resp.Result["1"]["1"]
+	day.Event = []string{"Nowruz", "Nowruz", "Spring", "Nowruz"}
+	resp.Result["1"]["1"] = day
+	data, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api.body = string(data)
+	holidays, err := APIProvider{URL: api.URL}.Holidays(context.Background(), 1404)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, h := range holidays {
+		if h.Date == (Date{Year: 1404, Month: 1, Day: 1}) && h.Name != "Nowruz; Spring" {
+			t.Errorf("holiday name = %q, want %q", h.Name, "Nowruz; Spring")
+		}
+	}
+}
+
+func TestValidateResponse(t *testing.T) {
+	api := &fakeAPI{holidays: testHolidays, status: true}
+	setDay := func(resp CalendarResponse, month, day string, solar DateInfo) {
+		d := resp.Result[month][day]
+		d.Solar = solar
+		resp.Result[month][day] = d
+	}
+	tests := []struct {
+		name   string
+		month  int
+		mutate func(CalendarResponse) CalendarResponse
+		want   string
+	}{
+		{"valid year", 0, func(r CalendarResponse) CalendarResponse { return r }, ""},
+		{"valid month", 7, func(r CalendarResponse) CalendarResponse { return r }, ""},
+		{"status false", 0, func(r CalendarResponse) CalendarResponse { r.Status = false; return r }, "status is false"},
+		{"too few days", 0, func(r CalendarResponse) CalendarResponse {
+			for m := 2; m <= 12; m++ {
+				delete(r.Result, strconv.Itoa(m))
+			}
+			return r
+		}, "31 days for year 1404, expected at least 300"},
+		{"other year", 0, func(r CalendarResponse) CalendarResponse {
+			setDay(r, "1", "5", DateInfo{Year: 1403, Month: 1, Day: 5})
+			return r
+		}, "got 1403/01/05 for year 1404"},
+		{"month out of range", 0, func(r CalendarResponse) CalendarResponse {
+			setDay(r, "1", "5", DateInfo{Year: 1404, Month: 13, Day: 5})
+			return r
+		}, "invalid date 1404/13/05"},
+		{"day out of range", 0, func(r CalendarResponse) CalendarResponse {
+			setDay(r, "7", "30", DateInfo{Year: 1404, Month: 7, Day: 31})
+			return r
+		}, "invalid date 1404/07/31"},
+		{"no leap day", 0, func(r CalendarResponse) CalendarResponse {
+			setDay(r, "12", "29", DateInfo{Year: 1404, Month: 12, Day: 30})
+			return r
+		}, "invalid date 1404/12/30"},
+		{"zero day", 7, func(r CalendarResponse) CalendarResponse {
+			setDay(r, "7", "1", DateInfo{Year: 1404, Month: 7})
+			return r
+		}, "invalid date 1404/07/00"},
+	}
+	for _, tt := range tests {
+		err := validateResponse(tt.mutate(api.response(1404, tt.month)), 1404, tt.month)
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("%s: error = %v, want one containing %q", tt.name, err, tt.want)
+		case err != nil && !strings.HasPrefix(err.Error(), "unexpected API response"):
+			t.Errorf("%s: error %q does not start with \"unexpected API response\"", tt.name, err)
+		}
+	}
+}
+
+func TestFixedProviderTable(t *testing.T) {
+	builtin, err := FixedProvider{}.Holidays(context.Background(), 1404)
+	if err != nil || len(builtin) != len(fixedHolidays) {
+		t.Fatalf("built-in table: %d holidays, %v, want %d", len(builtin), err, len(fixedHolidays))
+	}
+
+	p := FixedProvider{Table: []FixedHoliday{
+		{Month: 1, Day: 1, Name: "جشن نوروز"},
+		{Month: 12, Day: 30, Name: "leap day"},
+	}}
+	for _, tt := range []struct {
+		year, want int
+	}{{1403, 2}, {1404, 1}} {
+		holidays, err := p.Holidays(context.Background(), tt.year)
+		if err != nil || len(holidays) != tt.want {
+			t.Errorf("Holidays(%d) = %v, %v, want %d holidays", tt.year, holidays, err, tt.want)
+		}
+	}
+}
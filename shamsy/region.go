@@ -0,0 +1,45 @@
+package shamsy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultRegion is the region used when Options.Region is empty: Iran, whose
+// official holidays the pnldev.com API serves.
+const DefaultRegion = "ir"
+
+// regionProviders returns the default providers of each supported region.
+// Supporting another region only takes an entry here.
+var regionProviders = map[string]func() []Provider{
+	"ir": func() []Provider { return []Provider{APIProvider{}} },
+}
+
+// Regions returns the supported regions, sorted.
+func Regions() []string {
+	regions := make([]string, 0, len(regionProviders))
+	for r := range regionProviders {
+		regions = append(regions, r)
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// RegionProviders returns the providers asked for the holidays of region
+// when Options.Providers is nil. An empty region means DefaultRegion.
+func RegionProviders(region string) ([]Provider, error) {
+	providers, ok := regionProviders[normalizeRegion(region)]
+	if !ok {
+		return nil, fmt.Errorf("unknown region %q (supported: %s)", region, strings.Join(Regions(), ", "))
+	}
+	return providers(), nil
+}
+
+// normalizeRegion lowercases a region code, mapping "" to DefaultRegion.
+func normalizeRegion(region string) string {
+	if region == "" {
+		return DefaultRegion
+	}
+	return strings.ToLower(region)
+}
@@ -0,0 +1,103 @@
+package shamsy
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SchemaCheck is the outcome of the lightweight checks APIProvider runs on
+// every response it accepts. They look for the signs of a changed response
+// format that still decodes, which would otherwise show up as a calendar
+// silently missing its holidays.
+type SchemaCheck struct {
+	// Year and Month are the query; Month is 0 for a full year.
+	Year, Month int
+	// Days is the number of days listed and Holidays the number of them
+	// parsed as holidays.
+	Days, Holidays int
+	// Nowruz is set when 1 Farvardin was parsed as a holiday. It is only
+	// checked for queries that include Farvardin.
+	Nowruz bool
+	// Problems lists the expectations that failed; empty means the
+	// response looks as expected.
+	Problems []string
+}
+
+// OK reports whether every expectation held.
+func (c SchemaCheck) OK() bool {
+	return len(c.Problems) == 0
+}
+
+// String summarizes the check, e.g. "1404: 365 days, 27 holidays, Nowruz
+// present".
+func (c SchemaCheck) String() string {
+	query := fmt.Sprintf("%d", c.Year)
+	if c.Month > 0 {
+		query = fmt.Sprintf("%d/%02d", c.Year, c.Month)
+	}
+	s := fmt.Sprintf("%s: %d days, %d holidays", query, c.Days, c.Holidays)
+	if c.Month <= 1 {
+		if c.Nowruz {
+			s += ", Nowruz present"
+		} else {
+			s += ", Nowruz missing"
+		}
+	}
+	return s
+}
+
+// responseKeys are the top-level keys of a CalendarResponse and dayKeys the
+// keys of each day without which its holiday would be lost.
+var (
+	responseKeys = []string{"status", "result"}
+	dayKeys      = []string{"solar", "holiday"}
+)
+
+// checkSchema runs the SchemaCheck expectations on a response body that
+// passed validateResponse and the holidays parsed from it.
+func checkSchema(body []byte, year, month int, calendar CalendarResponse, holidays []Holiday) SchemaCheck {
+	c := SchemaCheck{Year: year, Month: month, Holidays: len(holidays)}
+	var raw struct {
+		Result map[string]map[string]map[string]json.RawMessage `json:"result"`
+	}
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(body, &top); err == nil {
+		for _, key := range responseKeys {
+			if _, ok := top[key]; !ok {
+				c.Problems = append(c.Problems, fmt.Sprintf("top-level key %q is missing", key))
+			}
+		}
+	}
+	// Unknown day shapes only fail this decoding, not the response.
+	if err := json.Unmarshal(body, &raw); err == nil {
+	days:
+		for _, monthData := range raw.Result {
+			for _, day := range monthData {
+				for _, key := range dayKeys {
+					if _, ok := day[key]; !ok {
+						c.Problems = append(c.Problems, fmt.Sprintf("day key %q is missing", key))
+						break days
+					}
+				}
+			}
+		}
+	}
+	for _, monthData := range calendar.Result {
+		c.Days += len(monthData)
+	}
+	if c.Days == 0 {
+		c.Problems = append(c.Problems, "no days listed")
+	}
+	for _, h := range holidays {
+		if h.Date == (Date{Year: year, Month: 1, Day: 1}) {
+			c.Nowruz = true
+		}
+	}
+	if month == 0 && c.Holidays == 0 {
+		c.Problems = append(c.Problems, "no holidays in the whole year")
+	}
+	if month <= 1 && c.Days > 0 && !c.Nowruz {
+		c.Problems = append(c.Problems, "Nowruz (1 Farvardin) is not a holiday")
+	}
+	return c
+}
@@ -0,0 +1,82 @@
+package shamsy
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSchemaCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		month    int
+		holidays map[Date]string
+		drift    func(body string) string
+		want     string
+		problems []string
+	}{
+		{name: "year", holidays: testHolidays, want: "1404: 365 days, 2 holidays, Nowruz present"},
+		{name: "month", month: 4, holidays: testHolidays, want: "1404/04: 31 days, 1 holidays"},
+		{name: "farvardin", month: 1, holidays: testHolidays, want: "1404/01: 31 days, 1 holidays, Nowruz present"},
+		{name: "extra keys", holidays: testHolidays, drift: func(body string) string {
+			return strings.Replace(body, `{"status":true,`, `{"version":2,"status":true,`, 1)
+		}, want: "1404: 365 days, 2 holidays, Nowruz present"},
+		{name: "renamed holiday key", holidays: testHolidays, drift: func(body string) string {
+			return strings.ReplaceAll(body, `"holiday":`, `"is_holiday":`)
+		}, want: "1404: 365 days, 0 holidays, Nowruz missing", problems: []string{
+			`day key "holiday" is missing`,
+			"no holidays in the whole year",
+			"Nowruz (1 Farvardin) is not a holiday",
+		}},
+		{name: "no Nowruz", holidays: map[Date]string{{Year: 1404, Month: 4, Day: 15}: "Ashura"},
+			want: "1404: 365 days, 1 holidays, Nowruz missing", problems: []string{"Nowruz (1 Farvardin) is not a holiday"}},
+		{name: "no Nowruz outside Farvardin", month: 7, want: "1404/07: 30 days, 0 holidays"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newFakeAPI(t, tt.holidays)
+			if tt.drift != nil {
+				data, err := json.Marshal(api.response(1404, tt.month))
+				if err != nil {
+					t.Fatal(err)
+				}
+				api.body = tt.drift(string(data))
+			}
+			var checks []SchemaCheck
+			p := APIProvider{URL: api.URL, OnCheck: func(c SchemaCheck) { checks = append(checks, c) }}
+			var err error
+			if tt.month == 0 {
+				_, err = p.Holidays(context.Background(), 1404)
+			} else {
+				_, err = p.MonthHolidays(context.Background(), 1404, tt.month)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(checks) != 1 {
+				t.Fatalf("got %d checks, want 1", len(checks))
+			}
+			c := checks[0]
+			if c.String() != tt.want {
+				t.Errorf("String() = %q, want %q", c.String(), tt.want)
+			}
+			if !reflect.DeepEqual(c.Problems, tt.problems) || c.OK() != (len(tt.problems) == 0) {
+				t.Errorf("Problems = %q, OK() = %v, want %q", c.Problems, c.OK(), tt.problems)
+			}
+		})
+	}
+}
+
+func TestSchemaCheckMissingTopLevelKey(t *testing.T) {
+	calendar := (&fakeAPI{holidays: testHolidays, status: true}).response(1404, 7)
+	data, err := json.Marshal(calendar.Result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := checkSchema([]byte(`{"result":`+string(data)+`}`), 1404, 7, calendar, nil)
+	if want := []string{`top-level key "status" is missing`}; !reflect.DeepEqual(c.Problems, want) {
+		t.Errorf("Problems = %q, want %q", c.Problems, want)
+	}
+}
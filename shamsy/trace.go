@@ -0,0 +1,73 @@
+package shamsy
+
+// Trace records the intermediate values of a conversion, for explaining how
+// a result was computed.
+type Trace struct {
+	// JDN is the Julian Day Number shared by the Shamsi and Gregorian date.
+	JDN int
+	// ShamsiEpochDays counts the days since 1 Farvardin 1 and
+	// GregorianEpochDays those since 1 January 1 (proleptic).
+	ShamsiEpochDays    int
+	GregorianEpochDays int
+	// Cycle and CycleYear decompose the Shamsi year as
+	// year+1595 = 33*Cycle + CycleYear. Each 33-year cycle has 8 leap years
+	// and within a cycle every fourth year is one, so LeapDays =
+	// 8*Cycle + (CycleYear+3)/4 leap days precede the year.
+	Cycle     int
+	CycleYear int
+	LeapDays  int
+	// NowruzJDN is the JDN of 1 Farvardin of the Shamsi year.
+	NowruzJDN int
+	// DayOfYear is the 1-based day of the Shamsi year.
+	DayOfYear int
+	// BeforeNowruz is set when a Gregorian date falls before Nowruz of
+	// Shamsi year gy-621, so that it belongs to year gy-622.
+	BeforeNowruz bool
+	// SecondHalf is set when the day falls in the 30-day months (Mehr to
+	// Esfand) rather than the 31-day months.
+	SecondHalf bool
+}
+
+// newTrace fills in the values shared by both conversion directions.
+func newTrace(j, jy int) Trace {
+	y := jy + 1595
+	t := Trace{
+		JDN:                j,
+		ShamsiEpochDays:    j - ShamsyJDN(MinYear, 1, 1),
+		GregorianEpochDays: j - GregorianJDN(1, 1, 1),
+		Cycle:              y / 33,
+		CycleYear:          y % 33,
+		NowruzJDN:          ShamsyJDN(jy, 1, 1),
+	}
+	t.LeapDays = 8*t.Cycle + (t.CycleYear+3)/4
+	t.DayOfYear = j - t.NowruzJDN + 1
+	t.SecondHalf = t.DayOfYear > 186
+	return t
+}
+
+// ToGregorianWithTrace is ToGregorian returning the intermediate values too.
+func ToGregorianWithTrace(jy, jm, jd int) (int, int, int, Trace) {
+	j := ShamsyJDN(jy, jm, jd)
+	gy, gm, gd := gregorianFromJDN(j)
+	return gy, gm, gd, newTrace(j, jy)
+}
+
+// FromGregorianWithTrace is FromGregorian returning the intermediate values
+// too.
+func FromGregorianWithTrace(gy, gm, gd int) (int, int, int, Trace) {
+	j := GregorianJDN(gy, gm, gd)
+	// Nowruz of Shamsi year Y falls in March of Gregorian year Y+621.
+	jy := gy - 621
+	before := j < ShamsyJDN(jy, 1, 1)
+	if before {
+		jy--
+	}
+	t := newTrace(j, jy)
+	t.BeforeNowruz = before
+	doy := t.DayOfYear - 1
+	if !t.SecondHalf {
+		return jy, doy/31 + 1, doy%31 + 1, t
+	}
+	doy -= 186
+	return jy, doy/30 + 7, doy%30 + 1, t
+}
@@ -0,0 +1,51 @@
+package shamsy
+
+import "testing"
+
+func TestConversionTrace(t *testing.T) {
+	tests := []struct {
+		name    string
+		convert func() (int, int, int, Trace)
+		a, b, c int
+		want    Trace
+	}{
+		{
+			name:    "ToGregorian 1404/07/10",
+			convert: func() (int, int, int, Trace) { return ToGregorianWithTrace(1404, 7, 10) },
+			a:       2025, b: 10, c: 2,
+			want: Trace{JDN: 2460951, ShamsiEpochDays: 512631, GregorianEpochDays: 739525,
+				Cycle: 90, CycleYear: 29, LeapDays: 728, NowruzJDN: 2460756, DayOfYear: 196, SecondHalf: true},
+		},
+		{
+			name:    "FromGregorian 2025-10-02",
+			convert: func() (int, int, int, Trace) { return FromGregorianWithTrace(2025, 10, 2) },
+			a:       1404, b: 7, c: 10,
+			want: Trace{JDN: 2460951, ShamsiEpochDays: 512631, GregorianEpochDays: 739525,
+				Cycle: 90, CycleYear: 29, LeapDays: 728, NowruzJDN: 2460756, DayOfYear: 196, SecondHalf: true},
+		},
+		{
+			// The day before Nowruz 1404 is the leap day of 1403.
+			name:    "FromGregorian 2025-03-20",
+			convert: func() (int, int, int, Trace) { return FromGregorianWithTrace(2025, 3, 20) },
+			a:       1403, b: 12, c: 30,
+			want: Trace{JDN: 2460755, ShamsiEpochDays: 512435, GregorianEpochDays: 739329,
+				Cycle: 90, CycleYear: 28, LeapDays: 727, NowruzJDN: 2460390, DayOfYear: 366, BeforeNowruz: true, SecondHalf: true},
+		},
+		{
+			name:    "FromGregorian 2025-03-21",
+			convert: func() (int, int, int, Trace) { return FromGregorianWithTrace(2025, 3, 21) },
+			a:       1404, b: 1, c: 1,
+			want: Trace{JDN: 2460756, ShamsiEpochDays: 512436, GregorianEpochDays: 739330,
+				Cycle: 90, CycleYear: 29, LeapDays: 728, NowruzJDN: 2460756, DayOfYear: 1},
+		},
+	}
+	for _, tt := range tests {
+		a, b, c, trace := tt.convert()
+		if a != tt.a || b != tt.b || c != tt.c {
+			t.Errorf("%s = %d/%d/%d, want %d/%d/%d", tt.name, a, b, c, tt.a, tt.b, tt.c)
+		}
+		if trace != tt.want {
+			t.Errorf("%s: trace\n%+v, want\n%+v", tt.name, trace, tt.want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package shamsy
+
+// Shamsi week dates number the weeks of a Shamsi year. Weeks run from
+// Saturday to Friday, week 1 is the one containing 1 Farvardin, and the days
+// of a week are numbered 1 (Saturday) to 7 (Friday). The first and last
+// weeks are usually partial: their days outside the year belong to the
+// neighbouring year's weeks. A year has 53 weeks, or 54 when a leap year
+// starts on a Friday.
+
+// weekColumn returns the position of a date in its Saturday-first week,
+// 0 for Saturday through 6 for Friday.
+func weekColumn(jy, jm, jd int) int {
+	return (int(GregorianWeekday(ToGregorian(jy, jm, jd))) + 1) % 7
+}
+
+// Week returns the Shamsi week date of d: the week of its year and the day
+// of that week.
+func (d Date) Week() (week, day int) {
+	offset := weekColumn(d.Year, 1, 1)
+	index := ShamsyJDN(d.Year, d.Month, d.Day) - ShamsyJDN(d.Year, 1, 1) + offset
+	return index/7 + 1, index%7 + 1
+}
+
+// WeeksInYear returns the number of weeks of Shamsi year jy.
+func WeeksInYear(jy int) int {
+	week, _ := Date{Year: jy, Month: 12, Day: MonthDays(jy, 12)}.Week()
+	return week
+}
+
+// DateFromWeek returns the day of Shamsi year jy with the given week date;
+// it is the inverse of Week. ok is false when the week or day is out of
+// range or the day falls outside the year, in the part of week 1 or of the
+// last week that belongs to the neighbouring year.
+func DateFromWeek(jy, week, day int) (d Date, ok bool) {
+	if week < 1 || week > WeeksInYear(jy) || day < 1 || day > 7 {
+		return Date{}, false
+	}
+	first := ShamsyJDN(jy, 1, 1)
+	jdn := first - weekColumn(jy, 1, 1) + 7*(week-1) + day - 1
+	last := ShamsyJDN(jy, 12, MonthDays(jy, 12))
+	if jdn < first || jdn > last {
+		return Date{}, false
+	}
+	return DateFromGregorian(gregorianFromJDN(jdn)), true
+}
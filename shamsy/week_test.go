@@ -0,0 +1,72 @@
+package shamsy
+
+import "testing"
+
+func TestWeekRoundTrip(t *testing.T) {
+	for jy := 1395; jy <= 1420; jy++ {
+		weeks := WeeksInYear(jy)
+		for jm := 1; jm <= 12; jm++ {
+			for jd := 1; jd <= MonthDays(jy, jm); jd++ {
+				d := Date{Year: jy, Month: jm, Day: jd}
+				week, day := d.Week()
+				if week < 1 || week > weeks || day != weekColumn(jy, jm, jd)+1 {
+					t.Fatalf("%v.Week() = %d, %d; the year has %d weeks", d, week, day, weeks)
+				}
+				if got, ok := DateFromWeek(jy, week, day); !ok || got != d {
+					t.Fatalf("DateFromWeek(%d, %d, %d) = %v, %v, want %v", jy, week, day, got, ok, d)
+				}
+			}
+		}
+		// The weekdays run on across the year boundary.
+		_, last := Date{Year: jy, Month: 12, Day: MonthDays(jy, 12)}.Week()
+		if _, first := (Date{Year: jy + 1, Month: 1, Day: 1}).Week(); first != last%7+1 {
+			t.Errorf("%d ends on day %d but %d starts on day %d", jy, last, jy+1, first)
+		}
+	}
+}
+
+func TestWeeksInYear(t *testing.T) {
+	tests := []struct {
+		jy, weeks int
+	}{
+		{1398, 53},
+		{1399, 54}, // leap, starts on a Friday
+		{1403, 53}, // leap, starts on a Wednesday
+		{1404, 53},
+		{1405, 53}, // starts on a Saturday
+		{1416, 54},
+	}
+	for _, tt := range tests {
+		if got := WeeksInYear(tt.jy); got != tt.weeks {
+			t.Errorf("WeeksInYear(%d) = %d, want %d", tt.jy, got, tt.weeks)
+		}
+	}
+}
+
+func TestDateFromWeekBoundaries(t *testing.T) {
+	tests := []struct {
+		jy, week, day int
+		want          Date
+		ok            bool
+	}{
+		{1404, 1, 7, Date{Year: 1404, Month: 1, Day: 1}, true},
+		{1404, 1, 6, Date{}, false}, // 1403/12/30
+		{1404, 28, 1, Date{Year: 1404, Month: 6, Day: 29}, true},
+		{1404, 53, 7, Date{Year: 1404, Month: 12, Day: 29}, true},
+		{1403, 53, 6, Date{Year: 1403, Month: 12, Day: 30}, true},
+		{1403, 53, 7, Date{}, false}, // 1404/01/01
+		{1405, 1, 1, Date{Year: 1405, Month: 1, Day: 1}, true},
+		{1399, 1, 7, Date{Year: 1399, Month: 1, Day: 1}, true},
+		{1399, 54, 1, Date{Year: 1399, Month: 12, Day: 30}, true},
+		{1399, 54, 2, Date{}, false},
+		{1404, 54, 1, Date{}, false},
+		{1404, 0, 1, Date{}, false},
+		{1404, 10, 0, Date{}, false},
+		{1404, 10, 8, Date{}, false},
+	}
+	for _, tt := range tests {
+		if got, ok := DateFromWeek(tt.jy, tt.week, tt.day); got != tt.want || ok != tt.ok {
+			t.Errorf("DateFromWeek(%d, %d, %d) = %v, %v, want %v, %v", tt.jy, tt.week, tt.day, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// WeekdayCount is one row of stats --weekdays: how often a weekday occurs in
+// the period and how many of those days are holidays.
+type WeekdayCount struct {
+	Weekday  string `json:"weekday"`
+	Days     int    `json:"days"`
+	Holidays int    `json:"holidays"`
+}
+
+// WeekdayStats is the result of stats --weekdays for a Shamsi year or month
+// (Month 0 for the whole year). MostHolidays names the weekday the most
+// holidays fall on, empty when there are none; ties go to the earlier
+// weekday of the Shamsi week.
+type WeekdayStats struct {
+	Year         int            `json:"year"`
+	Month        int            `json:"month,omitempty"`
+	Weekdays     []WeekdayCount `json:"weekdays"`
+	MostHolidays string         `json:"mostHolidays,omitempty"`
+}
+
+// weekdayStats counts the weekdays of a Shamsi year, or of one month when
+// month > 0, in Shamsi week order (Saturday first).
+func weekdayStats(jy, jm int, holidays *shamsy.HolidayCalendar) WeekdayStats {
+	stats := WeekdayStats{Year: jy, Month: jm, Weekdays: make([]WeekdayCount, 7)}
+	for i, name := range []string{"Saturday", "Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday"} {
+		stats.Weekdays[i].Weekday = name
+	}
+	first, last := 1, 12
+	if jm > 0 {
+		first, last = jm, jm
+	}
+	for m := first; m <= last; m++ {
+		for d := 1; d <= shamsy.MonthDays(jy, m); d++ {
+			gy, gm, gd := toGregorian(jy, m, d)
+			count := &stats.Weekdays[goToshamsyWeekday[int(shamsy.GregorianWeekday(gy, gm, gd))]]
+			count.Days++
+			if _, ok := holidays.IsHoliday(shamsy.Date{Year: jy, Month: m, Day: d}); ok {
+				count.Holidays++
+			}
+		}
+	}
+	most := 0
+	for _, c := range stats.Weekdays {
+		if c.Holidays > most {
+			most, stats.MostHolidays = c.Holidays, c.Weekday
+		}
+	}
+	return stats
+}
+
+// handleStats implements "stats --weekdays YEAR [MONTH] [--json]".
+func handleStats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	weekdays := fs.Bool("weekdays", false, "Count the weekdays and the holidays on each")
+	fs.BoolVar(&jsonOutput, "json", jsonOutput, "Print JSON")
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if !*weekdays || len(positional) < 1 || len(positional) > 2 {
+		return withCode(codeUsage, fmt.Errorf("usage: shamsy-calendar stats --weekdays YEAR [MONTH] [--json]"))
+	}
+	var jy, jm int
+	if len(positional) == 2 {
+		if jy, jm, err = parseYearMonth(positional[0], positional[1]); err != nil {
+			return err
+		}
+	} else if jy, err = strconv.Atoi(positional[0]); err != nil || !shamsy.InRange(jy) {
+		return withCode(codeInvalidArgument, fmt.Errorf("invalid year argument %q", positional[0]))
+	}
+	holidays, err := fetchMonthHolidays(jy, jm)
+	if err != nil {
+		return err
+	}
+	stats := weekdayStats(jy, jm, holidays)
+	if jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return checkStdout(enc.Encode(stats))
+	}
+
+	title := withIcon("📊", fmt.Sprintf("Weekdays in %d", jy))
+	if jm > 0 {
+		title = withIcon("📊", fmt.Sprintf("Weekdays in %s %d", shamsyMonths[jm-1], jy))
+	}
+	fmt.Println(rgb(cyan, strings.Repeat("=", 60)))
+	fmt.Println(rgb(purple, title))
+	fmt.Println(rgb(cyan, strings.Repeat("-", 60)))
+	fmt.Println(rgb(green, fmt.Sprintf("%-10s %5s %9s", "Weekday", "Days", "Holidays")))
+	for _, c := range stats.Weekdays {
+		fmt.Printf("%s %s %s\n", rgb(cyan, fmt.Sprintf("%-10s", c.Weekday)),
+			rgb(blue, fmt.Sprintf("%5d", c.Days)), rgb(offday, fmt.Sprintf("%9d", c.Holidays)))
+	}
+	fmt.Println(rgb(cyan, strings.Repeat("-", 60)))
+	most := "none"
+	if stats.MostHolidays != "" {
+		for _, c := range stats.Weekdays {
+			if c.Weekday == stats.MostHolidays {
+				most = fmt.Sprintf("%s (%d)", c.Weekday, c.Holidays)
+			}
+		}
+	}
+	fmt.Printf("%s: %s\n", rgb(green, "Most holidays on"), rgb(offday, most))
+	printFixedOnlyNote()
+	fmt.Println(rgb(cyan, strings.Repeat("=", 60)))
+	return nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// fixedCalendar returns the fixed national holidays of a Shamsi year.
+func fixedCalendar(t *testing.T, year int) *shamsy.HolidayCalendar {
+	t.Helper()
+	holidays, err := shamsy.FixedProvider{}.Holidays(context.Background(), year)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return shamsy.Merge().WithHolidays(holidays)
+}
+
+func TestWeekdayStats(t *testing.T) {
+	tests := []struct {
+		name            string
+		jy, jm          int
+		days, holidays  [7]int // Saturday first
+		mostHolidays    string
+		withoutHolidays bool
+	}{
+		{
+			// 1404 has 365 days from a Friday, so it has 53 Fridays.
+			name: "1404", jy: 1404,
+			days:         [7]int{52, 52, 52, 52, 52, 52, 53},
+			holidays:     [7]int{1, 1, 1, 1, 3, 1, 2},
+			mostHolidays: "Wednesday",
+		},
+		{
+			// Leap year 1403 starts on a Wednesday and ends on a Thursday.
+			name: "1403", jy: 1403,
+			days:         [7]int{52, 52, 52, 52, 53, 53, 52},
+			holidays:     [7]int{1, 1, 3, 1, 2, 1, 1},
+			mostHolidays: "Monday",
+		},
+		{
+			name: "Farvardin 1404", jy: 1404, jm: 1,
+			days:         [7]int{5, 5, 4, 4, 4, 4, 5},
+			holidays:     [7]int{1, 1, 1, 1, 1, 0, 1},
+			mostHolidays: "Saturday",
+		},
+		{
+			name: "Mehr 1404 without holidays", jy: 1404, jm: 7,
+			days:            [7]int{4, 4, 4, 5, 5, 4, 4},
+			withoutHolidays: true,
+		},
+	}
+	for _, tt := range tests {
+		holidays := fixedCalendar(t, tt.jy)
+		if tt.withoutHolidays {
+			holidays = nil
+		}
+		stats := weekdayStats(tt.jy, tt.jm, holidays)
+		if stats.Year != tt.jy || stats.Month != tt.jm || len(stats.Weekdays) != 7 {
+			t.Fatalf("%s: got %+v", tt.name, stats)
+		}
+		for i, c := range stats.Weekdays {
+			if c.Days != tt.days[i] || c.Holidays != tt.holidays[i] {
+				t.Errorf("%s: %s = %d days, %d holidays, want %d, %d", tt.name, c.Weekday, c.Days, c.Holidays, tt.days[i], tt.holidays[i])
+			}
+		}
+		if stats.MostHolidays != tt.mostHolidays {
+			t.Errorf("%s: most holidays on %q, want %q", tt.name, stats.MostHolidays, tt.mostHolidays)
+		}
+	}
+}
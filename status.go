@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+	"golang.org/x/term"
+)
+
+// statusReporter shows the progress of long-running operations on stderr.
+// Warnings go through it as well, so they never interleave with a spinner.
+type statusReporter interface {
+	Start(msg string) // an operation began
+	Done()            // the current operation finished
+	Warn(msg string)  // print a warning line
+}
+
+// status reports the progress of holiday fetches and scal's warnings. It is
+// a spinner when stderr is a terminal and plain lines otherwise.
+var status = newStatusReporter()
+
+func newStatusReporter() statusReporter {
+	if term.IsTerminal(int(os.Stderr.Fd())) {
+		return newSpinnerStatus()
+	}
+	return lineStatus{}
+}
+
+// lineStatus prints one line per operation and warning, for logs and pipes.
+type lineStatus struct{}
+
+func (lineStatus) Start(msg string) { fmt.Fprintln(os.Stderr, msg) }
+func (lineStatus) Done()            {}
+func (lineStatus) Warn(msg string)  { fmt.Fprintf(os.Stderr, "Warning: %s\n", msg) }
+
+// showFetchProgress reports the library's fetch stages through status.
+func showFetchProgress(stage string) {
+	switch stage {
+	case shamsy.StageFetch:
+		status.Start("Fetching holidays...")
+	case shamsy.StageDone:
+		status.Done()
+	}
+}
@@ -0,0 +1,7 @@
+//go:build noprogressbar
+
+package main
+
+// newSpinnerStatus falls back to plain lines in builds without the
+// progressbar dependency (go build -tags noprogressbar).
+func newSpinnerStatus() statusReporter { return lineStatus{} }
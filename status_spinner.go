@@ -0,0 +1,48 @@
+//go:build !noprogressbar
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/schollz/progressbar/v3"
+)
+
+// spinnerStatus shows a spinner while an operation runs. Warnings clear it
+// first and redraw it afterwards.
+type spinnerStatus struct {
+	bar *progressbar.ProgressBar
+}
+
+func newSpinnerStatus() statusReporter { return &spinnerStatus{} }
+
+func (s *spinnerStatus) Start(msg string) {
+	if s.bar != nil {
+		s.bar.Describe(msg)
+		return
+	}
+	s.bar = progressbar.NewOptions(-1,
+		progressbar.OptionSetWriter(os.Stderr),
+		progressbar.OptionSetDescription(msg),
+		progressbar.OptionSpinnerType(14),
+		progressbar.OptionSetWidth(20),
+	)
+}
+
+func (s *spinnerStatus) Done() {
+	if s.bar != nil {
+		s.bar.Close()
+		s.bar = nil
+	}
+}
+
+func (s *spinnerStatus) Warn(msg string) {
+	if s.bar != nil {
+		s.bar.Clear()
+	}
+	fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+	if s.bar != nil {
+		s.bar.RenderBlank()
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// readStdinDate reads the date of "-c -" from stdin: a single date, with
+// surrounding whitespace and blank lines ignored.
+func readStdinDate() (string, error) {
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("failed to read a date from stdin: %v", err)
+	}
+	var dates []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			dates = append(dates, line)
+		}
+	}
+	switch len(dates) {
+	case 0:
+		return "", withCode(codeInvalidDate, fmt.Errorf("no date on stdin for -c -"))
+	case 1:
+		return dates[0], nil
+	}
+	return "", withCode(codeInvalidArgument, fmt.Errorf("stdin holds %d dates but -c - converts a single one; run scal once per line, e.g. with xargs -n1 scal -c", len(dates)))
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// maxStripDays bounds --strip to a year of days.
+const maxStripDays = 366
+
+// handleStrip implements "--strip START N": N consecutive days from START
+// (Gregorian with -g, or "today") in a horizontal strip, each day under its
+// weekday label and the months named above their first day. The strip wraps
+// to further rows only when it is wider than the terminal.
+func handleStrip(startStr, nStr string, isGregorian bool) error {
+	n, err := strconv.Atoi(nStr)
+	if err != nil || n < 1 || n > maxStripDays {
+		return withCode(codeInvalidArgument, fmt.Errorf("invalid --strip length %q: expected a number of days from 1 to %d", nStr, maxStripDays))
+	}
+	today := currentDate()
+	from := today
+	if !strings.EqualFold(startStr, "today") {
+		if _, _, _, from, err = parseCalendarDate(startStr, isGregorian); err != nil {
+			return err
+		}
+	}
+	to := shamsy.DateFromEpochDays(from.EpochDays() + n - 1)
+	if !shamsy.InRange(to.Year) {
+		return withCode(codeInvalidArgument, fmt.Errorf("--strip %s %d ends outside the supported range %s", startStr, n, supportedRange(isGregorian)))
+	}
+	holidays, err := holidaysBetween(from, to)
+	if err != nil {
+		return err
+	}
+
+	cw := monthOptions{}.cellWidth()
+	perRow := n
+	if width := terminalWidth(); width > 0 && width/cw < perRow {
+		perRow = max(width/cw, 7)
+	}
+	title := fmt.Sprintf("%s – %s (%d days)", stripDate(from, isGregorian), stripDate(to, isGregorian), n)
+	fmt.Println(rgb(red, title))
+	for start := 0; start < n; start += perRow {
+		if start > 0 {
+			fmt.Println()
+		}
+		count := min(perRow, n-start)
+		var months, labels, days strings.Builder
+		for i := 0; i < count; i++ {
+			d := shamsy.DateFromEpochDays(from.EpochDays() + start + i)
+			g := gregorianOf(d)
+			weekday := shamsy.GregorianWeekday(g.Year, g.Month, g.Day)
+			highlight := noHighlight
+			_, holiday := holidays.IsHoliday(d)
+			var label string
+			var color Color
+			if isGregorian {
+				if d == today {
+					highlight = g.Day
+				}
+				label = gregorianWeekLabels()[weekday]
+				color = gregorianDayColor(g.Year, g.Month, g.Day, highlight, holidays)
+				days.WriteString(rgb(color, dayCell(g.Day, cw, holiday)))
+				if i == 0 || g.Day == 1 {
+					stripMonthLabel(&months, i*cw, gregorianMonthTitle(g.Year, g.Month))
+				}
+			} else {
+				if d == today {
+					highlight = d.Day
+				}
+				label = shamsyWeekHeader()[(int(weekday)+1)%7]
+				color = shamsyDayColor(d.Year, d.Month, d.Day, highlight, holidays)
+				days.WriteString(rgb(color, dayCell(d.Day, cw, holiday)))
+				if i == 0 || d.Day == 1 {
+					stripMonthLabel(&months, i*cw, shamsyMonthTitle(d.Year, d.Month))
+				}
+			}
+			labels.WriteString(labelCell(label, cw))
+		}
+		fmt.Println(rgb(purple, strings.TrimRight(months.String(), " ")))
+		fmt.Println(rgb(green, labels.String()))
+		fmt.Println(days.String())
+	}
+	printFixedOnlyNote()
+	return nil
+}
+
+// stripDate formats a day of the strip title in the strip's calendar.
+func stripDate(d shamsy.Date, isGregorian bool) string {
+	if isGregorian {
+		g := gregorianOf(d)
+		return shamsy.FormatGregorian(g.Year, g.Month, g.Day, "YYYY/MM/DD")
+	}
+	return d.String()
+}
+
+// stripMonthLabel writes a month name at column col of the month line. A name
+// that would run into the previous one replaces its end.
+func stripMonthLabel(line *strings.Builder, col int, name string) {
+	text := line.String()
+	width := visibleWidth(text)
+	switch {
+	case width < col:
+		text += strings.Repeat(" ", col-width)
+	case width > col:
+		runes := []rune(text)
+		for len(runes) > 0 && visibleWidth(string(runes)) > max(col-1, 0) {
+			runes = runes[:len(runes)-1]
+		}
+		text = string(runes)
+		text += strings.Repeat(" ", col-visibleWidth(text))
+	}
+	line.Reset()
+	line.WriteString(text + name)
+}
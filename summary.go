@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// noSummary hides the footer printed after a single month.
+var noSummary bool
+
+// printShamsyMonthSummary prints the working days, holidays and first and
+// last weekday of a Shamsi month.
+func printShamsyMonthSummary(jy, jm int, holidays *shamsy.HolidayCalendar) {
+	days := shamsy.MonthDays(jy, jm)
+	first := shamsy.Date{Year: jy, Month: jm, Day: 1}
+	last := shamsy.Date{Year: jy, Month: jm, Day: days}
+	entries := holidays.HolidaysIn(jy, jm)
+	onFriday := 0
+	for _, h := range entries {
+		if h.Date.Weekday() == time.Friday {
+			onFriday++
+		}
+	}
+	printMonthSummary(holidays.WorkingDays(first, last), len(entries),
+		"Friday", fmt.Sprintf("%d on Fridays", onFriday), gregorianOf(first).DayWeek, gregorianOf(last).DayWeek)
+}
+
+// printGregorianMonthSummary prints the same footer for a Gregorian month,
+// counting the Saturday/Sunday weekend like the Gregorian view does.
+func printGregorianMonthSummary(year, month int, shamsyHolidays *shamsy.HolidayCalendar) {
+	days := gregorianMonthDays(year, month)
+	working, holidayCount, onWeekend := 0, 0, 0
+	for d := 1; d <= days; d++ {
+		weekday := shamsy.GregorianWeekday(year, month, d)
+		weekend := weekday == time.Saturday || weekday == time.Sunday
+		_, holiday := shamsyHolidays.IsGregorianHoliday(year, month, d)
+		if holiday {
+			holidayCount++
+			if weekend {
+				onWeekend++
+			}
+		}
+		if !holiday && !weekend {
+			working++
+		}
+	}
+	printMonthSummary(working, holidayCount, "weekend", fmt.Sprintf("%d on weekends", onWeekend),
+		shamsy.WeekdayName(year, month, 1), shamsy.WeekdayName(year, month, days))
+}
+
+func printMonthSummary(working, holidayCount int, weekend, offDays, firstDay, lastDay string) {
+	fmt.Printf("%s: %s\n", rgb(green, "Working days"), rgb(cyan, fmt.Sprint(working)))
+	fmt.Printf("%s: %s\n", rgb(green, "Holidays"), rgb(offday, fmt.Sprintf("%d (%s)", holidayCount, offDays)))
+	fmt.Printf("%s: %s\n", rgb(green, "First/last day"), rgb(cyan, fmt.Sprintf("%s / %s", firstDay, lastDay)))
+	printLegend(weekend)
+	printFixedOnlyNote()
+	printEstimateNote()
+}
+
+// printLegend explains the colors of the grid: official holidays and the
+// weekend, named weekend, that is not a holiday. Without colors there is
+// nothing to explain.
+func printLegend(weekend string) {
+	if noColor {
+		return
+	}
+	legend := fmt.Sprintf("%s  %s", rgb(offday, "■ holiday"), rgb(weekendColor, "■ "+weekend))
+	if len(estimated) > 0 {
+		legend += "  " + rgb(estimatedColor, "■ estimated holiday")
+	}
+	fmt.Printf("%s: %s\n", rgb(green, "Legend"), legend)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"golang.org/x/term"
+)
+
+// terminalWidth returns the width of the terminal stdout is attached to,
+// falling back to $COLUMNS. It returns 0 when the width is unknown, e.g. when
+// the output is piped, in which case layouts are never adapted.
+func terminalWidth() int {
+	if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+		return w
+	}
+	if w, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && w > 0 {
+		return w
+	}
+	return 0
+}
+
+// fitMonth adapts a single-month layout to the terminal: months that do not
+// fit switch to the mini layout. With strict set it returns an error instead.
+func fitMonth(opts monthOptions, strict bool) (monthOptions, error) {
+	available := terminalWidth()
+	if available == 0 || opts.monthWidth() <= available {
+		return opts, nil
+	}
+	if strict {
+		return opts, fmt.Errorf("month view needs %d columns but the terminal has %d", opts.monthWidth(), available)
+	}
+	opts.Mini = true
+	return opts, nil
+}
+
+// fitYear picks how many month columns (4, 3, 2 or 1) of the year view fit in
+// the terminal, switching to the mini layout when not even one does. With
+// strict set a terminal narrower than the full 4-column view is an error.
+func fitYear(opts monthOptions, strict bool) (int, monthOptions, error) {
+	available := terminalWidth()
+	if available == 0 {
+		return 4, opts, nil
+	}
+	if strict && yearWidth(4, opts) > available {
+		return 0, opts, fmt.Errorf("year view needs %d columns but the terminal has %d", yearWidth(4, opts), available)
+	}
+	for _, cols := range []int{4, 3, 2, 1} {
+		if yearWidth(cols, opts) <= available {
+			return cols, opts, nil
+		}
+	}
+	opts.Mini = true
+	return 1, opts, nil
+}
+
+// fitQuarterGrid adapts the 3-column quarter grid to the terminal, switching
+// to the mini layout when it does not fit. With strict set it returns an
+// error instead.
+func fitQuarterGrid(opts monthOptions, strict bool) (monthOptions, error) {
+	available := terminalWidth()
+	if available == 0 || yearWidth(3, opts) <= available {
+		return opts, nil
+	}
+	if strict {
+		return opts, fmt.Errorf("quarter grid needs %d columns but the terminal has %d", yearWidth(3, opts), available)
+	}
+	opts.Mini = true
+	return opts, nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+//go:embed data/holiday_names.json
+var holidayNamesJSON []byte
+
+// holidayNames maps normalized Persian holiday names to English. It is loaded
+// from data/holiday_names.json, or from the bundle installed by update-data;
+// extend that file to add translations.
+var holidayNames map[string]string
+
+// translateHolidays shows English holiday names where they are known.
+var translateHolidays bool
+
+// verbose enables diagnostic messages on stderr.
+var verbose bool
+
+// reportedUntranslated remembers names already reported under --verbose.
+var reportedUntranslated = map[string]bool{}
+
+func init() {
+	raw := map[string]string{}
+	if err := json.Unmarshal(holidayNamesJSON, &raw); err != nil {
+		panic(fmt.Sprintf("invalid embedded holiday_names.json: %v", err))
+	}
+	setHolidayNames(raw)
+}
+
+// setHolidayNames replaces the translation table with raw, which maps Persian
+// names as they appear in holiday_names.json to English.
+func setHolidayNames(raw map[string]string) {
+	holidayNames = make(map[string]string, len(raw))
+	for fa, en := range raw {
+		holidayNames[normalizeHolidayName(fa)] = en
+	}
+}
+
+// normalizeHolidayName folds the spelling differences seen in holiday names,
+// see shamsy.NormalizeName.
+func normalizeHolidayName(name string) string {
+	return shamsy.NormalizeName(name)
+}
+
+// holidayText returns a holiday description for display. With --translate
+// each "; "-separated event is replaced by its English name when known and
+// kept in Persian otherwise.
+func holidayText(desc string) string {
+	if !translateHolidays {
+		return desc
+	}
+	events := strings.Split(desc, "; ")
+	for i, event := range events {
+		if en, ok := holidayNames[normalizeHolidayName(event)]; ok {
+			events[i] = en
+		} else if verbose && !reportedUntranslated[event] {
+			reportedUntranslated[event] = true
+			fmt.Fprintf(os.Stderr, "translate: no English name for %q (add it to data/holiday_names.json)\n", event)
+		}
+	}
+	return strings.Join(events, "; ")
+}
@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestHolidayText(t *testing.T) {
+	saved, savedNames := translateHolidays, holidayNames
+	t.Cleanup(func() { translateHolidays, holidayNames = saved, savedNames })
+	setHolidayNames(map[string]string{"عید سعید فطر": "Eid al-Fitr", "جشن نوروز": "Nowruz"})
+
+	tests := []struct {
+		desc      string
+		translate bool
+		want      string
+	}{
+		{"عید سعید فطر", false, "عید سعید فطر"},
+		{"عید سعید فطر", true, "Eid al-Fitr"},
+		// Arabic yeh, tatweel and a fatha still match the entry.
+		{"عيد سعـيد\u064e فطر", true, "Eid al-Fitr"},
+		{"جشن نوروز; عید سعید فطر", true, "Nowruz; Eid al-Fitr"},
+		{"جشن نوروز; روز ناشناخته", true, "Nowruz; روز ناشناخته"},
+	}
+	for _, tt := range tests {
+		translateHolidays = tt.translate
+		if got := holidayText(tt.desc); got != tt.want {
+			t.Errorf("holidayText(%q) with translate=%v = %q, want %q", tt.desc, tt.translate, got, tt.want)
+		}
+	}
+}
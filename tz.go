@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"time"
+	// Embedded so that the default zone works on systems without tzdata.
+	_ "time/tzdata"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// defaultTimeZone is the zone whose date is "today" unless --tz names
+// another one, so that users abroad see Tehran's today by default.
+const defaultTimeZone = "Asia/Tehran"
+
+// todayLocation is the time zone today's date is taken in.
+var todayLocation = time.Local
+
+// setTimeZone loads the IANA zone of --tz. A zone that cannot be loaded
+// leaves the local time zone in use, with a warning.
+func setTimeZone(name string) {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		status.Warn(fmt.Sprintf("unknown time zone %q (%v); using local time", name, err))
+		return
+	}
+	todayLocation = loc
+}
+
+// startTime is when scal started. Today is derived from it alone, so that
+// the highlighted day, the weekend colors and the summaries of a run agree
+// even when the run crosses midnight.
+var startTime = time.Now()
+
+// currentTime returns the time scal started, in todayLocation.
+func currentTime() time.Time {
+	return startTime.In(todayLocation)
+}
+
+// currentDate returns today's Shamsi date in todayLocation, as of
+// currentTime.
+func currentDate() shamsy.Date {
+	now := currentTime()
+	return dateFromGregorian(now.Year(), int(now.Month()), now.Day())
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+func TestCurrentDateNearMidnight(t *testing.T) {
+	tehran := time.FixedZone("IRST", 7*30*60)
+	pacific := time.FixedZone("PDT", -7*60*60)
+	tests := []struct {
+		name    string
+		at      string // UTC
+		loc     *time.Location
+		want    shamsy.Date
+		weekday time.Weekday
+	}{
+		{"last second of 1403 in Tehran", "2025-03-20T20:29:59Z", tehran, shamsy.Date{Year: 1403, Month: 12, Day: 30}, time.Thursday},
+		{"Nowruz midnight in Tehran", "2025-03-20T20:30:00Z", tehran, shamsy.Date{Year: 1404, Month: 1, Day: 1}, time.Friday},
+		{"same instant in UTC", "2025-03-20T20:30:00Z", time.UTC, shamsy.Date{Year: 1403, Month: 12, Day: 30}, time.Thursday},
+		{"before midnight west of UTC", "2025-10-03T06:59:59Z", pacific, shamsy.Date{Year: 1404, Month: 7, Day: 10}, time.Thursday},
+		{"midnight west of UTC", "2025-10-03T07:00:00Z", pacific, shamsy.Date{Year: 1404, Month: 7, Day: 11}, time.Friday},
+	}
+	for _, tt := range tests {
+		at, err := time.Parse(time.RFC3339, tt.at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		setClock(t, at, tt.loc)
+		today := currentDate()
+		if today != tt.want {
+			t.Errorf("%s: currentDate() = %v, want %v", tt.name, today, tt.want)
+			continue
+		}
+		// The highlighted day and its weekday come from the same date, so
+		// the weekend coloring cannot disagree with the highlight.
+		if wd := shamsy.GregorianWeekday(shamsy.ToGregorian(today.Year, today.Month, today.Day)); wd != tt.weekday || currentTime().Weekday() != wd {
+			t.Errorf("%s: weekday %v, clock weekday %v, want %v", tt.name, wd, currentTime().Weekday(), tt.weekday)
+		}
+		if c := shamsyDayColor(today.Year, today.Month, today.Day, today.Day, nil); c != yellow {
+			t.Errorf("%s: today is colored %v, want the highlight", tt.name, c)
+		}
+		if tt.weekday == time.Friday {
+			if c := shamsyDayColor(today.Year, today.Month, today.Day, noHighlight, nil); c != weekendColor {
+				t.Errorf("%s: an unhighlighted Friday is colored %v, want the weekend color", tt.name, c)
+			}
+		}
+	}
+}
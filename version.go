@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// version is the release of scal, set at build time with
+// -ldflags "-X main.version=v1.2.3"; otherwise taken from the module
+// version go install records.
+var version = ""
+
+// printVersion prints scal's version and the holiday data it uses.
+func printVersion() {
+	v := version
+	if v == "" {
+		v = "devel"
+		if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+			v = info.Main.Version
+		}
+	}
+	fmt.Printf("shamsy-calendar %s\n", v)
+	if dataVersion != "" {
+		fmt.Printf("data bundle %s\n", dataVersion)
+	} else {
+		fmt.Println("data bundle: embedded")
+	}
+}
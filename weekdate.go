@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// weekDatePattern matches a week date, YYYY-Www-D, with or without the
+// hyphens.
+var weekDatePattern = regexp.MustCompile(`^(\d{1,4})-?[Ww](\d{1,2})-?(\d)$`)
+
+// parseWeekDate parses a week date: an ISO 8601 one (Monday is day 1) with
+// isGregorian, otherwise a Shamsi one as defined by shamsy.Date.Week
+// (Saturday is day 1, week 1 contains 1 Farvardin). ok is false when dateStr
+// is not in the week date form.
+func parseWeekDate(dateStr string, isGregorian bool) (gy, gm, gd int, date shamsy.Date, ok bool, err error) {
+	m := weekDatePattern.FindStringSubmatch(dateStr)
+	if m == nil {
+		return 0, 0, 0, shamsy.Date{}, false, nil
+	}
+	year, _ := strconv.Atoi(m[1])
+	week, _ := strconv.Atoi(m[2])
+	day, _ := strconv.Atoi(m[3])
+	if isGregorian {
+		gy, gm, gd, err = isoWeekToGregorian(year, week, day, dateStr)
+		if err != nil {
+			return 0, 0, 0, shamsy.Date{}, true, err
+		}
+		return gy, gm, gd, dateFromGregorian(gy, gm, gd), true, nil
+	}
+	if !shamsy.InRange(year) {
+		return 0, 0, 0, shamsy.Date{}, true, withCode(codeInvalidDate, fmt.Errorf("%s is outside the supported range %s", dateStr, supportedRange(false)))
+	}
+	date, ok = shamsy.DateFromWeek(year, week, day)
+	if !ok {
+		return 0, 0, 0, shamsy.Date{}, true, withCode(codeInvalidDate, fmt.Errorf("invalid Shamsi week date %s: %d has weeks 1-%d and days 1-7 (Saturday-Friday), and the days of its first and last week outside the year belong to the neighbouring year", dateStr, year, shamsy.WeeksInYear(year)))
+	}
+	gy, gm, gd = toGregorian(date.Year, date.Month, date.Day)
+	return gy, gm, gd, date, true, nil
+}
+
+// gweekPattern matches the gweek:YEAR/WEEK/DAY form of HR and payroll
+// exports, an ISO 8601 week date in either calendar mode.
+var gweekPattern = regexp.MustCompile(`^(?i:gweek):(\d{1,4})/(\d{1,2})/(\d)$`)
+
+// parseGWeek parses a gweek:YEAR/WEEK/DAY date, e.g. gweek:2025/14/3 for the
+// Wednesday of ISO week 14 of 2025. ok is false when dateStr is not in that
+// form.
+func parseGWeek(dateStr string) (gy, gm, gd int, date shamsy.Date, ok bool, err error) {
+	m := gweekPattern.FindStringSubmatch(dateStr)
+	if m == nil {
+		if strings.HasPrefix(strings.ToLower(dateStr), "gweek:") {
+			return 0, 0, 0, shamsy.Date{}, true, withCode(codeInvalidDate, fmt.Errorf("invalid date %q, expected gweek:YEAR/WEEK/DAY, e.g. gweek:2025/14/3", dateStr))
+		}
+		return 0, 0, 0, shamsy.Date{}, false, nil
+	}
+	year, _ := strconv.Atoi(m[1])
+	week, _ := strconv.Atoi(m[2])
+	day, _ := strconv.Atoi(m[3])
+	if gy, gm, gd, err = isoWeekToGregorian(year, week, day, dateStr); err != nil {
+		return 0, 0, 0, shamsy.Date{}, true, err
+	}
+	return gy, gm, gd, dateFromGregorian(gy, gm, gd), true, nil
+}
+
+// isoWeekToGregorian returns the Gregorian date of ISO 8601 week week of
+// year, day 1 being Monday. The week must exist in the year, which has 52
+// or 53 weeks.
+func isoWeekToGregorian(year, week, day int, dateStr string) (int, int, int, error) {
+	if !yearInRange(year, true) {
+		return 0, 0, 0, withCode(codeInvalidDate, fmt.Errorf("%s is outside the supported range %s", dateStr, supportedRange(true)))
+	}
+	weeks := isoWeeksInYear(year)
+	if week < 1 || week > weeks || day < 1 || day > 7 {
+		return 0, 0, 0, withCode(codeInvalidDate, fmt.Errorf("invalid ISO week date %s: %d has weeks 1-%d and days 1-7 (Monday-Sunday)", dateStr, year, weeks))
+	}
+	// Week 1 is the week containing 4 January.
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	monday := jan4.AddDate(0, 0, -((int(jan4.Weekday()) + 6) % 7))
+	t := monday.AddDate(0, 0, 7*(week-1)+day-1)
+	if !shamsy.GregorianInRange(t.Year(), int(t.Month()), t.Day()) {
+		return 0, 0, 0, withCode(codeInvalidDate, fmt.Errorf("%s is outside the supported range %s", dateStr, supportedRange(true)))
+	}
+	return t.Year(), int(t.Month()), t.Day(), nil
+}
+
+// isoWeeksInYear returns the number of ISO 8601 weeks of Gregorian year y,
+// 52 or 53: the week of 28 December.
+func isoWeeksInYear(y int) int {
+	_, week := time.Date(y, time.December, 28, 0, 0, 0, 0, time.UTC).ISOWeek()
+	return week
+}
+
+// gweekDate formats the ISO 8601 week date of a Gregorian date in the
+// YEAR/WEEK/DAY form of gweek:, e.g. 2025/14/3.
+func gweekDate(gy, gm, gd int) string {
+	t := time.Date(gy, time.Month(gm), gd, 0, 0, 0, 0, time.UTC)
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%d/%d/%d", year, week, (int(t.Weekday())+6)%7+1)
+}
+
+// isoWeekDate formats the ISO 8601 week date of a Gregorian date, e.g.
+// 2025-W40-6.
+func isoWeekDate(gy, gm, gd int) string {
+	t := time.Date(gy, time.Month(gm), gd, 0, 0, 0, 0, time.UTC)
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%04d-W%02d-%d", year, week, (int(t.Weekday())+6)%7+1)
+}
+
+// shamsiWeekDate formats the Shamsi week date of d, e.g. 1404-W28-1.
+func shamsiWeekDate(d shamsy.Date) string {
+	week, day := d.Week()
+	return fmt.Sprintf("%04d-W%02d-%d", d.Year, week, day)
+}
+
+// showWeekDates adds the week dates of the converted day to -c with
+// --week-date.
+var showWeekDates bool
+
+// printWeekDates prints the Shamsi and ISO week dates of a day for -c.
+func printWeekDates(date shamsy.Date, gy, gm, gd int) {
+	if !showWeekDates {
+		return
+	}
+	fmt.Printf("%s: %s\n", rgb(green, "Week Date (Shamsi)"), rgb(yellow, shamsiWeekDate(date)))
+	fmt.Printf("%s: %s\n", rgb(green, "Week Date (ISO)"), rgb(blue, isoWeekDate(gy, gm, gd)))
+}
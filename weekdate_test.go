@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+func TestParseWeekDate(t *testing.T) {
+	tests := []struct {
+		in         string
+		gregorian  bool
+		gy, gm, gd int
+		wantErr    string
+	}{
+		{in: "2025-W40-6", gregorian: true, gy: 2025, gm: 10, gd: 4},
+		{in: "2025w406", gregorian: true, gy: 2025, gm: 10, gd: 4},
+		{in: "2020-W53-5", gregorian: true, gy: 2021, gm: 1, gd: 1},
+		{in: "2026-W01-1", gregorian: true, gy: 2025, gm: 12, gd: 29},
+		{in: "2026-W53-7", gregorian: true, gy: 2027, gm: 1, gd: 3},
+		{in: "2021-W53-1", gregorian: true, wantErr: "2021 has weeks 1-52"},
+		{in: "2025-W40-8", gregorian: true, wantErr: "days 1-7"},
+		{in: "1404-W28-1", gy: 2025, gm: 9, gd: 20},
+		{in: "1404-W01-7", gy: 2025, gm: 3, gd: 21},
+		{in: "1399-W54-1", gy: 2021, gm: 3, gd: 20},
+		{in: "1404-W01-6", wantErr: "belong to the neighbouring year"},
+		{in: "1404-W54-1", wantErr: "1404 has weeks 1-53"},
+	}
+	for _, tt := range tests {
+		gy, gm, gd, date, ok, err := parseWeekDate(tt.in, tt.gregorian)
+		if !ok {
+			t.Errorf("parseWeekDate(%q) did not recognize a week date", tt.in)
+			continue
+		}
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) || errorCode(err) != codeInvalidDate {
+				t.Errorf("parseWeekDate(%q) = %v, want an %s error containing %q", tt.in, err, codeInvalidDate, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || gy != tt.gy || gm != tt.gm || gd != tt.gd || date != shamsy.DateFromGregorian(gy, gm, gd) {
+			t.Errorf("parseWeekDate(%q) = %d-%d-%d %v, %v, want %d-%d-%d", tt.in, gy, gm, gd, date, err, tt.gy, tt.gm, tt.gd)
+		}
+	}
+	if _, _, _, _, ok, _ := parseWeekDate("1404/07/10", false); ok {
+		t.Error("parseWeekDate recognized a calendar date")
+	}
+}
+
+func TestWeekDateRoundTrip(t *testing.T) {
+	// Every day around the turn of the years, including the 53rd ISO weeks
+	// of 2020 and 2026 and the 54th Shamsi week of 1399.
+	for _, year := range []int{2021, 2022, 2025, 2026, 2027} {
+		newYear := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		nowruz := time.Date(year, time.March, 20, 0, 0, 0, 0, time.UTC)
+		for offset := -10; offset <= 10; offset++ {
+			g := newYear.AddDate(0, 0, offset)
+			y, m, d := g.Year(), int(g.Month()), g.Day()
+			gotY, gotM, gotD, _, _, err := parseWeekDate(isoWeekDate(y, m, d), true)
+			if err != nil || gotY != y || gotM != m || gotD != d {
+				t.Errorf("%s: got %d-%d-%d, %v, want %d-%d-%d", isoWeekDate(y, m, d), gotY, gotM, gotD, err, y, m, d)
+			}
+
+			g = nowruz.AddDate(0, 0, offset)
+			want := shamsy.DateFromGregorian(g.Year(), int(g.Month()), g.Day())
+			_, _, _, got, _, err := parseWeekDate(shamsiWeekDate(want), false)
+			if err != nil || got != want {
+				t.Errorf("%s: got %v, %v, want %v", shamsiWeekDate(want), got, err, want)
+			}
+		}
+	}
+}
+
+func TestParseGWeek(t *testing.T) {
+	// Years beginning on each weekday, Monday to Sunday: the first week
+	// starts up to three days before 1 January or after it, and the years
+	// beginning on a Thursday, or a Wednesday in a leap year, have 53 weeks.
+	tests := []struct {
+		year, weeks   int
+		first, latest string // Monday of week 1, Sunday of the last week
+	}{
+		{2018, 52, "2018-01-01", "2018-12-30"},
+		{2019, 52, "2018-12-31", "2019-12-29"},
+		{2020, 53, "2019-12-30", "2021-01-03"},
+		{2015, 53, "2014-12-29", "2016-01-03"},
+		{2016, 52, "2016-01-04", "2017-01-01"},
+		{2022, 52, "2022-01-03", "2023-01-01"},
+		{2023, 52, "2023-01-02", "2023-12-31"},
+	}
+	format := func(gy, gm, gd int) string { return fmt.Sprintf("%04d-%02d-%02d", gy, gm, gd) }
+	for _, tt := range tests {
+		if got := isoWeeksInYear(tt.year); got != tt.weeks {
+			t.Errorf("isoWeeksInYear(%d) = %d, want %d", tt.year, got, tt.weeks)
+		}
+		for _, c := range []struct {
+			week, day int
+			want      string
+		}{{1, 1, tt.first}, {tt.weeks, 7, tt.latest}} {
+			in := fmt.Sprintf("gweek:%d/%d/%d", tt.year, c.week, c.day)
+			gy, gm, gd, date, ok, err := parseGWeek(in)
+			if !ok || err != nil || format(gy, gm, gd) != c.want || date != shamsy.DateFromGregorian(gy, gm, gd) {
+				t.Errorf("parseGWeek(%q) = %s %v, %v, %v, want %s", in, format(gy, gm, gd), date, ok, err, c.want)
+				continue
+			}
+			if got := gweekDate(gy, gm, gd); got != fmt.Sprintf("%d/%d/%d", tt.year, c.week, c.day) {
+				t.Errorf("gweekDate(%s) = %s, want %d/%d/%d", c.want, got, tt.year, c.week, c.day)
+			}
+		}
+		in := fmt.Sprintf("gweek:%d/%d/1", tt.year, tt.weeks+1)
+		if _, _, _, _, ok, err := parseGWeek(in); !ok || err == nil || errorCode(err) != codeInvalidDate {
+			t.Errorf("parseGWeek(%q) = %v, %v, want an %s error", in, ok, err, codeInvalidDate)
+		}
+	}
+
+	invalid := []struct {
+		in, want string
+	}{
+		{"gweek:2025/14/0", "days 1-7"},
+		{"gweek:2025/0/1", "2025 has weeks 1-52"},
+		{"GWEEK:2025-14-3", "expected gweek:YEAR/WEEK/DAY"},
+		{"gweek:", "expected gweek:YEAR/WEEK/DAY"},
+	}
+	for _, tt := range invalid {
+		if _, _, _, _, ok, err := parseGWeek(tt.in); !ok || err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("parseGWeek(%q) = %v, %v, want an error containing %q", tt.in, ok, err, tt.want)
+		}
+	}
+	if gy, gm, gd, date, ok, err := parseGWeek("GWeek:2025/14/3"); !ok || err != nil || format(gy, gm, gd) != "2025-04-02" || date != (shamsy.Date{Year: 1404, Month: 1, Day: 13}) {
+		t.Errorf("parseGWeek(GWeek:2025/14/3) = %s %v, %v, %v, want 2025-04-02 1404/01/13", format(gy, gm, gd), date, ok, err)
+	}
+	if _, _, _, _, ok, _ := parseGWeek("2025/14/3"); ok {
+		t.Error("parseGWeek recognized a date without the gweek: prefix")
+	}
+}
+
+func TestFormatsGWeekJSON(t *testing.T) {
+	saved := jsonOutput
+	jsonOutput = true
+	defer func() { jsonOutput = saved }()
+	var err error
+	out := captureStdout(func() { err = handleFormats("gweek:2025/14/3", false, "gweek,shamsi") })
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]string
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("%v: %s", err, out)
+	}
+	if got["gweek"] != "2025/14/3" || got["shamsi"] != "1404/01/13" {
+		t.Errorf("handleFormats = %v, want gweek 2025/14/3 and shamsi 1404/01/13", got)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// weekRows returns how many week rows (4, 5 or 6) a month whose 1st falls on
+// weekday column first and that has days days occupies in the grid, or week
+// columns in the transposed layout.
+func weekRows(first, days int) int {
+	return (first + days + 6) / 7
+}
+
+// handleWeekRows prints the number of week rows of the month of dateStr,
+// a Shamsi date or, with isGregorian, a Gregorian one laid out with the
+// --week-start in effect.
+func handleWeekRows(dateStr string, isGregorian bool) error {
+	gy, gm, _, sh, err := parseCalendarDate(dateStr, isGregorian)
+	if err != nil {
+		return err
+	}
+	if isGregorian {
+		fmt.Println(weekRows(getGregorianFirstWeekday(gy, gm), gregorianMonthDays(gy, gm)))
+	} else {
+		fmt.Println(weekRows(getFirstWeekday(sh.Year, sh.Month), shamsy.MonthDays(sh.Year, sh.Month)))
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// gregorianWeekStart is the weekday in the first column of the Gregorian
+// view, Sunday unless --week-start says otherwise.
+var gregorianWeekStart = time.Sunday
+
+// gregorianColumn returns the column of a weekday in the Gregorian view.
+func gregorianColumn(weekday time.Weekday) int {
+	return (int(weekday) - int(gregorianWeekStart) + 7) % 7
+}
+
+// gregorianWeekHeader returns the Gregorian weekday labels in column order.
+func gregorianWeekHeader() []string {
+	header := make([]string, 7)
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		header[gregorianColumn(wd)] = gregorianWeekLabels()[wd]
+	}
+	return header
+}
+
+// isoWeekOfRow returns the ISO 8601 week number of the Gregorian grid row
+// whose first column is day rowStart of the month; rowStart may be zero or
+// negative for a row starting in the previous month. A row is numbered after
+// its Monday, which can lie in the neighbouring month or year: the last days
+// of December often belong to week 1 of the next year, and the first days of
+// January to week 52 or 53 of the previous one.
+func isoWeekOfRow(year, month, rowStart int) int {
+	monday := rowStart + gregorianColumn(time.Monday)
+	_, week := time.Date(year, time.Month(month), monday, 0, 0, 0, 0, time.UTC).ISOWeek()
+	return week
+}
+
+// parseWeekStart parses the --week-start weekday.
+func parseWeekStart(s string) (time.Weekday, error) {
+	wd, ok := parseWeekday(s)
+	if !ok {
+		return 0, withCode(codeInvalidArgument, fmt.Errorf("invalid weekday %q in --week-start", s))
+	}
+	return wd, nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setWeekStart sets the Gregorian week start for the test.
+func setWeekStart(t *testing.T, wd time.Weekday) {
+	saved := gregorianWeekStart
+	gregorianWeekStart = wd
+	t.Cleanup(func() { gregorianWeekStart = saved })
+}
+
+func TestParseWeekStart(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Weekday
+		wantErr bool
+	}{
+		{"sunday", time.Sunday, false},
+		{"Monday", time.Monday, false},
+		{"mo", time.Monday, false},
+		{" sat ", time.Saturday, false},
+		{"m", 0, true},
+		{"funday", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseWeekStart(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseWeekStart(%q) = %v, %v", tt.in, got, err)
+		}
+	}
+}
+
+func TestGregorianWeekHeader(t *testing.T) {
+	tests := []struct {
+		start time.Weekday
+		want  []string
+	}{
+		{time.Sunday, []string{"Su", "Mo", "Tu", "We", "Th", "Fr", "Sa"}},
+		{time.Monday, []string{"Mo", "Tu", "We", "Th", "Fr", "Sa", "Su"}},
+		{time.Saturday, []string{"Sa", "Su", "Mo", "Tu", "We", "Th", "Fr"}},
+	}
+	for _, tt := range tests {
+		setWeekStart(t, tt.start)
+		if got := gregorianWeekHeader(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("start %v: header %v, want %v", tt.start, got, tt.want)
+		}
+	}
+}
+
+func TestGregorianFirstWeekday(t *testing.T) {
+	// June 2025 starts on a Sunday and July 2025 on a Tuesday.
+	tests := []struct {
+		start       time.Weekday
+		month, want int
+	}{
+		{time.Sunday, 6, 0},
+		{time.Monday, 6, 6},
+		{time.Sunday, 7, 2},
+		{time.Monday, 7, 1},
+		{time.Saturday, 7, 3},
+	}
+	for _, tt := range tests {
+		setWeekStart(t, tt.start)
+		if got := getGregorianFirstWeekday(2025, tt.month); got != tt.want {
+			t.Errorf("start %v: first weekday of 2025/%d = %d, want %d", tt.start, tt.month, got, tt.want)
+		}
+	}
+}
+
+func TestGregorianCalendarMondayStart(t *testing.T) {
+	setWeekStart(t, time.Monday)
+	want := strings.Join([]string{
+		"==========June 2025===========",
+		"  Mo  Tu  We  Th  Fr  Sa  Su",
+		"                           1",
+		"   2   3   4   5   6   7   8",
+		"   9  10  11  12  13  14  15",
+		"  16  17  18  19  20  21  22",
+		"  23  24  25  26  27  28  29",
+		"  30                        ",
+	}, "\n") + "\n\n"
+	if got := renderText(func() { printGregorianCalendar(2025, 6, noHighlight, nil, monthOptions{}) }); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestISOWeekOfRow(t *testing.T) {
+	tests := []struct {
+		start          time.Weekday
+		year, month    int
+		rowStart, week int
+	}{
+		// 1 January 2021 is a Friday in week 53 of 2020.
+		{time.Monday, 2021, 1, -3, 53},
+		{time.Monday, 2021, 1, 4, 1},
+		{time.Sunday, 2021, 1, -4, 53},
+		{time.Sunday, 2021, 1, 3, 1},
+		{time.Saturday, 2021, 1, -5, 53},
+		{time.Saturday, 2021, 1, 2, 1},
+		// 29 December 2025 starts week 1 of 2026.
+		{time.Monday, 2025, 12, 22, 52},
+		{time.Monday, 2025, 12, 29, 1},
+		{time.Sunday, 2025, 12, 28, 1},
+		// 2026 has 53 weeks; its last reaches into January 2027.
+		{time.Monday, 2026, 12, 28, 53},
+		{time.Monday, 2027, 1, -3, 53},
+		{time.Monday, 2027, 1, 4, 1},
+	}
+	for _, tt := range tests {
+		setWeekStart(t, tt.start)
+		if got := isoWeekOfRow(tt.year, tt.month, tt.rowStart); got != tt.week {
+			t.Errorf("week start %v: isoWeekOfRow(%d, %d, %d) = %d, want %d", tt.start, tt.year, tt.month, tt.rowStart, got, tt.week)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Aria-Ghojavand/shamsy-calendar/shamsy"
+)
+
+// workingDaysLeft counts the working days after today up to the end of the
+// current month and of the current year, in the Shamsi calendar with its
+// Friday weekend or, with isGregorian, the Gregorian one with the
+// Saturday/Sunday weekend the Gregorian view uses. Holidays are loaded for
+// each Shamsi year the period reaches, so a Gregorian year also needs the
+// next Shamsi year. When they cannot be loaded only the weekend is counted
+// and weekendOnly is set.
+func workingDaysLeft(today shamsy.Date, isGregorian bool) (month, year int, weekendOnly bool) {
+	monthEnd := shamsy.Date{Year: today.Year, Month: today.Month, Day: shamsy.MonthDays(today.Year, today.Month)}
+	yearEnd := shamsy.Date{Year: today.Year, Month: 12, Day: shamsy.MonthDays(today.Year, 12)}
+	if isGregorian {
+		g := gregorianOf(today)
+		jy, jm, jd := fromGregorian(g.Year, g.Month, gregorianMonthDays(g.Year, g.Month))
+		monthEnd = shamsy.Date{Year: jy, Month: jm, Day: jd}
+		jy, jm, jd = fromGregorian(g.Year, 12, 31)
+		yearEnd = shamsy.Date{Year: jy, Month: jm, Day: jd}
+	}
+
+	var holidays *shamsy.HolidayCalendar
+	for y := today.Year; y <= yearEnd.Year; y++ {
+		cal, err := fetchHolidays(y)
+		if err != nil {
+			holidays, weekendOnly = nil, true
+			break
+		}
+		holidays = shamsy.Merge(holidays, cal)
+	}
+	for n := today.EpochDays() + 1; n <= yearEnd.EpochDays(); n++ {
+		d := shamsy.DateFromEpochDays(n)
+		weekday := d.Weekday()
+		weekend := weekday == time.Friday
+		if isGregorian {
+			weekend = weekday == time.Saturday || weekday == time.Sunday
+		}
+		if _, holiday := holidays.IsHoliday(d); weekend || holiday {
+			continue
+		}
+		year++
+		if d.Compare(monthEnd) <= 0 {
+			month++
+		}
+	}
+	return month, year, weekendOnly
+}
+
+// workingDaysLeftText formats workingDaysLeft for the today line, e.g.
+// "4 working days left in Mehr, 120 left in 1405".
+func workingDaysLeftText(today shamsy.Date, isGregorian bool) string {
+	month, year, weekendOnly := workingDaysLeft(today, isGregorian)
+	monthName, y := shamsyMonths[today.Month-1], today.Year
+	weekend := "Fridays"
+	if isGregorian {
+		g := gregorianOf(today)
+		monthName, y = gregorianMonths[g.Month-1], g.Year
+		weekend = "weekends"
+	}
+	text := fmt.Sprintf("%d working days left in %s, %d left in %d", month, monthName, year, y)
+	if weekendOnly {
+		text += fmt.Sprintf(" (holidays unavailable, only %s excluded)", weekend)
+	}
+	return text
+}
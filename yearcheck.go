@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Default thresholds below which a year argument is taken for a typo, e.g.
+// 87 for 1387. The config can change them with "min_shamsi_year" and
+// "min_gregorian_year".
+const (
+	defaultMinShamsiYear    = 1000
+	defaultMinGregorianYear = 1500
+)
+
+// forceYear accepts years below the threshold without asking.
+var forceYear bool
+
+// minYear returns the threshold of the calendar in use.
+func minYear(isGregorian bool) int {
+	if isGregorian {
+		if userConfig.MinGregorianYear > 0 {
+			return userConfig.MinGregorianYear
+		}
+		return defaultMinGregorianYear
+	}
+	if userConfig.MinShamsiYear > 0 {
+		return userConfig.MinShamsiYear
+	}
+	return defaultMinShamsiYear
+}
+
+// suggestYear guesses the year meant by a short year y by prefixing the
+// current century (or millennium for three digits), stepping back when that
+// lands too far in the future: 87 becomes 1387 and 5 becomes 1405 in 1405,
+// 403 becomes 1403. It reports false when there is no sensible guess.
+func suggestYear(y, current int) (int, bool) {
+	switch {
+	case y >= 0 && y < 100:
+		s := current - current%100 + y
+		if s > current+50 {
+			s -= 100
+		}
+		return s, true
+	case y >= 100 && y < 1000:
+		s := current - current%1000 + y
+		if s > current+100 {
+			return 0, false
+		}
+		return s, true
+	}
+	return 0, false
+}
+
+// checkYear accepts a year argument at or above the threshold. A smaller
+// one is likely a typo: on a terminal the user is asked to confirm it, and
+// otherwise, or with --json, it is an error unless --force-year is given.
+func checkYear(y int, isGregorian bool) error {
+	if forceYear || y >= minYear(isGregorian) {
+		return nil
+	}
+	current := currentDate().Year
+	if isGregorian {
+		current = currentTime().Year()
+	}
+	msg := fmt.Sprintf("year %d looks like a typo", y)
+	if s, ok := suggestYear(y, current); ok {
+		msg = fmt.Sprintf("year %d looks like a typo; did you mean %d?", y, s)
+	}
+	if jsonOutput || !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stderr.Fd())) {
+		return withCode(codeInvalidArgument, fmt.Errorf("%s (pass --force-year to use it)", msg))
+	}
+	fmt.Fprintf(os.Stderr, "Warning: %s\nShow year %d anyway? [y/N] ", msg, y)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if a := strings.ToLower(strings.TrimSpace(answer)); a == "y" || a == "yes" {
+		return nil
+	}
+	return withCode(codeInvalidArgument, fmt.Errorf("year %d not confirmed", y))
+}
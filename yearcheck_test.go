@@ -0,0 +1,87 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// setClock makes t the time scal started at, in loc, for the test.
+func setClock(tb testing.TB, t time.Time, loc *time.Location) {
+	savedStart, savedLoc := startTime, todayLocation
+	startTime, todayLocation = t, loc
+	tb.Cleanup(func() { startTime, todayLocation = savedStart, savedLoc })
+}
+
+func TestSuggestYear(t *testing.T) {
+	tests := []struct {
+		y, current int
+		want       int
+		ok         bool
+	}{
+		// Two digits take the current century.
+		{87, 1405, 1387, true},
+		{5, 1405, 1405, true},
+		{0, 1405, 1400, true},
+		{60, 1405, 1360, true},
+		{40, 1405, 1440, true},
+		{25, 2026, 2025, true},
+		{99, 2026, 1999, true},
+		// Three digits take the current millennium.
+		{403, 1405, 1403, true},
+		{387, 1405, 1387, true},
+		{25, 1405, 1425, true},
+		{600, 1405, 0, false},
+		{999, 2026, 0, false},
+		{1387, 1405, 0, false},
+		{-5, 1405, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := suggestYear(tt.y, tt.current)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("suggestYear(%d, %d) = %d, %v, want %d, %v", tt.y, tt.current, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestCheckYear(t *testing.T) {
+	// 16 October 2026 is 24 Mehr 1405.
+	setClock(t, time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC), time.UTC)
+	savedForce, savedConfig, savedJSON := forceYear, userConfig, jsonOutput
+	defer func() { forceYear, userConfig, jsonOutput = savedForce, savedConfig, savedJSON }()
+	// With --json a small year is an error rather than a question, even on
+	// a terminal.
+	jsonOutput = true
+
+	tests := []struct {
+		y         int
+		gregorian bool
+		force     bool
+		minShamsi int
+		want      string
+	}{
+		{1404, false, false, 0, ""},
+		{1000, false, false, 0, ""},
+		{1500, true, false, 0, ""},
+		{87, false, false, 0, "year 87 looks like a typo; did you mean 1387? (pass --force-year to use it)"},
+		{403, false, false, 0, "did you mean 1403?"},
+		{25, true, false, 0, "did you mean 2025?"},
+		{1499, true, false, 0, "year 1499 looks like a typo (pass --force-year to use it)"},
+		{87, false, true, 0, ""},
+		{900, false, false, 500, ""},
+		{400, false, false, 500, "did you mean 1400?"},
+	}
+	for _, tt := range tests {
+		forceYear = tt.force
+		userConfig.MinShamsiYear = tt.minShamsi
+		err := checkYear(tt.y, tt.gregorian)
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("checkYear(%d, %v): unexpected error %v", tt.y, tt.gregorian, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("checkYear(%d, %v) = %v, want an error containing %q", tt.y, tt.gregorian, err, tt.want)
+		case err != nil && errorCode(err) != codeInvalidArgument:
+			t.Errorf("checkYear(%d, %v): error code %s", tt.y, tt.gregorian, errorCode(err))
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// yearGap separates the month columns of the year view and the other
+// side-by-side layouts. --gap and --separator replace it.
+var yearGap = "    "
+
+// parseYearGap builds the column gap of --gap and --separator: gap spaces,
+// with the separator character, when given, in their middle, e.g. "  │  "
+// for a gap of 4 and "│".
+func parseYearGap(gap int, separator string) (string, error) {
+	if gap < 0 {
+		return "", withCode(codeInvalidArgument, fmt.Errorf("invalid --gap %d: must not be negative", gap))
+	}
+	if separator == "" {
+		return strings.Repeat(" ", gap), nil
+	}
+	if width := uniseg.StringWidth(separator); uniseg.GraphemeClusterCount(separator) != 1 || width < 1 || width > 2 {
+		return "", withCode(codeInvalidArgument, fmt.Errorf("invalid --separator %q: must be a single character", separator))
+	}
+	return strings.Repeat(" ", gap/2) + rgb(cyan, separator) + strings.Repeat(" ", gap-gap/2), nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseYearGap(t *testing.T) {
+	tests := []struct {
+		gap       int
+		separator string
+		want      string
+		wantErr   bool
+	}{
+		{gap: 4, want: "    "},
+		{gap: 0, want: ""},
+		{gap: 4, separator: "│", want: "  │  "},
+		{gap: 3, separator: "|", want: " |  "},
+		{gap: 0, separator: "|", want: "|"},
+		{gap: 2, separator: "｜", want: " ｜ "},
+		{gap: -1, wantErr: true},
+		{gap: 4, separator: "||", wantErr: true},
+		{gap: 4, separator: "\t", wantErr: true},
+	}
+	saved := noColor
+	noColor = true
+	defer func() { noColor = saved }()
+	for _, tt := range tests {
+		got, err := parseYearGap(tt.gap, tt.separator)
+		if tt.wantErr {
+			if err == nil || errorCode(err) != codeInvalidArgument {
+				t.Errorf("parseYearGap(%d, %q) = %q, %v, want an %s error", tt.gap, tt.separator, got, err, codeInvalidArgument)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseYearGap(%d, %q) = %q, %v, want %q", tt.gap, tt.separator, got, err, tt.want)
+		}
+	}
+}
+
+func TestYearGapSnapshot(t *testing.T) {
+	tests := []struct {
+		gap       int
+		separator string
+		want      []string
+	}{
+		{4, "", []string{
+			"========Farvardin 1404========    =======Ordibehesht 1404=======",
+			"  Sh  Ye  Do  Se  Ch  Pa  Jo        Sh  Ye  Do  Se  Ch  Pa  Jo  ",
+			"                           1                 1   2   3   4   5  ",
+		}},
+		{2, "|", []string{
+			"========Farvardin 1404======== | =======Ordibehesht 1404=======",
+			"  Sh  Ye  Do  Se  Ch  Pa  Jo   |   Sh  Ye  Do  Se  Ch  Pa  Jo  ",
+			"                           1   |            1   2   3   4   5  ",
+		}},
+		{0, "", []string{
+			"========Farvardin 1404===============Ordibehesht 1404=======",
+			"  Sh  Ye  Do  Se  Ch  Pa  Jo    Sh  Ye  Do  Se  Ch  Pa  Jo  ",
+			"                           1             1   2   3   4   5  ",
+		}},
+	}
+	savedGap := yearGap
+	defer func() { yearGap = savedGap }()
+	for _, tt := range tests {
+		out := renderText(func() {
+			var err error
+			if yearGap, err = parseYearGap(tt.gap, tt.separator); err != nil {
+				t.Fatal(err)
+			}
+			printYearRow(1, 2, monthOptions{}, func(m int, opts monthOptions) {
+				printshamsyCalendar(1404, m, noHighlight, nil, opts)
+			})
+		})
+		lines := strings.Split(out, "\n")
+		if len(lines) < len(tt.want) {
+			t.Fatalf("gap %d %q: got %d lines: %q", tt.gap, tt.separator, len(lines), out)
+		}
+		for i, want := range tt.want {
+			if lines[i] != want {
+				t.Errorf("gap %d %q: line %d = %q, want %q", tt.gap, tt.separator, i, lines[i], want)
+			}
+		}
+		if w := visibleWidth(lines[0]); w != yearWidth(2, monthOptions{}) {
+			t.Errorf("gap %d %q: width %d, want %d", tt.gap, tt.separator, w, yearWidth(2, monthOptions{}))
+		}
+	}
+}